@@ -0,0 +1,100 @@
+package golden
+
+import (
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+/*
+sourceLocation is the place in the test source where golden was called
+*/
+type sourceLocation struct {
+	file     string
+	line     int
+	function string
+}
+
+func (l sourceLocation) String() string {
+	return fmt.Sprintf("%s:%d", l.file, l.line)
+}
+
+/*
+pkg returns the name of the package of the test, without the _test suffix of
+external test packages. The import path in the function name may end in a
+folder with another name, or in a major version like /v2, so the name is read
+from the package clause of the test file.
+*/
+func (l sourceLocation) pkg() string {
+	name, err := packageName(l.file)
+	if err != nil {
+		name = symbolPackage(l.function)
+	}
+	return strings.TrimSuffix(name, "_test")
+}
+
+var packageNames sync.Map
+
+func packageName(file string) (string, error) {
+	if name, ok := packageNames.Load(file); ok {
+		return name.(string), nil
+	}
+	parsed, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.PackageClauseOnly)
+	if err != nil {
+		return "", err
+	}
+	packageNames.Store(file, parsed.Name.Name)
+	return parsed.Name.Name, nil
+}
+
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+/*
+symbolPackage guesses the package name from the import path in the function
+name, when the test source can't be read. Major version suffixes are skipped.
+*/
+func symbolPackage(function string) string {
+	importPath := function
+	if slash := strings.LastIndex(importPath, "/"); slash >= 0 {
+		if dot := strings.Index(importPath[slash:], "."); dot >= 0 {
+			importPath = importPath[:slash+dot]
+		}
+	} else if dot := strings.Index(importPath, "."); dot >= 0 {
+		importPath = importPath[:dot]
+	}
+	segments := strings.Split(importPath, "/")
+	name := segments[len(segments)-1]
+	suffix := ""
+	if strings.HasSuffix(name, "_test") {
+		name, suffix = strings.TrimSuffix(name, "_test"), "_test"
+	}
+	if majorVersion.MatchString(name) && len(segments) > 1 {
+		name = segments[len(segments)-2]
+	}
+	return name + suffix
+}
+
+/*
+callerLocation looks for the first caller in a test file, skipping the frames
+of golden itself
+*/
+func callerLocation() (sourceLocation, error) {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if strings.HasSuffix(frame.File, "_test.go") {
+			return sourceLocation{file: frame.File, line: frame.Line, function: frame.Function}, nil
+		}
+		if !more {
+			break
+		}
+	}
+	return sourceLocation{}, errors.New("could not find the test source calling golden")
+}
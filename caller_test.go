@@ -0,0 +1,45 @@
+package golden
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSourceLocationPkg(t *testing.T) {
+	t.Run("should read the package name from the test source", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "invoice_test.go")
+		err := os.WriteFile(file, []byte("package invoice_test\n"), 0666)
+		assert.NoError(t, err)
+
+		loc := sourceLocation{file: file, function: "example.com/shop/v2_test.TestInvoice"}
+		assert.Equal(t, "invoice", loc.pkg())
+	})
+
+	examples := []struct {
+		function string
+		pkg      string
+	}{
+		{function: "example.com/shop/billing.TestInvoice", pkg: "billing"},
+		{function: "example.com/shop/billing_test.TestInvoice.func1", pkg: "billing"},
+		{function: "example.com/shop/v2.TestInvoice", pkg: "shop"},
+		{function: "example.com/shop/v2_test.TestInvoice", pkg: "shop"},
+		{function: "shop.TestInvoice", pkg: "shop"},
+	}
+	for _, example := range examples {
+		t.Run("should guess the package name of "+example.function, func(t *testing.T) {
+			loc := sourceLocation{file: "missing_test.go", function: example.function}
+			assert.Equal(t, example.pkg, loc.pkg())
+		})
+	}
+}
+
+func TestNeedsSource(t *testing.T) {
+	assert.False(t, Config{}.needsSource())
+	assert.False(t, Config{layout: FlatLayout{}}.needsSource())
+	assert.False(t, Config{template: "{test}/{subtest}{ext}"}.needsSource())
+	assert.True(t, Config{layout: PackageLayout{}}.needsSource())
+	assert.True(t, Config{template: "{package}/{test}{ext}"}.needsSource())
+	assert.True(t, Config{template: "{file}/{test}{ext}"}.needsSource())
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"github.com/franiglesias/golden/internal/vfs"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestHunks(t *testing.T) {
+	var fs *vfs.MemFs
+	var stdout, stderr *bytes.Buffer
+
+	const snapshot = "name: Fran\nage: 30\ncity: Vigo\nzip: 36200\ncountry: Spain\nphone: 555\n"
+	const received = "name: Frank\nage: 30\ncity: Vigo\nzip: 36200\ncountry: Spain\nphone: 556\n"
+
+	setUp := func(t *testing.T) {
+		fs = vfs.NewMemFs()
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+		files := map[string]string{
+			"testdata/TestA.snap":          snapshot,
+			"testdata/TestA.received.snap": received,
+			"testdata/TestB.snap":          "b: old",
+			"testdata/TestB.received.snap": "b: new",
+		}
+		for name, content := range files {
+			err := fs.WriteFile(name, []byte(content))
+			assert.NoError(t, err)
+		}
+	}
+
+	run := func(keys string, args ...string) int {
+		return run(args, fs, strings.NewReader(keys), stdout, stderr)
+	}
+
+	assertNotExists := func(t *testing.T, name string) {
+		exists, err := fs.Exists(name)
+		assert.NoError(t, err)
+		assert.Falsef(t, exists, "%s should not exist", name)
+	}
+
+	t.Run("should show numbered hunks", func(t *testing.T) {
+		setUp(t)
+
+		code := run("", "hunks", "testdata/TestA.snap")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "=== testdata/TestA.snap\n"+
+			"[1] @@ line 1 @@\n-name: Fran\n+name: Frank\n age: 30\n city: Vigo\n zip: 36200\n"+
+			"[2] @@ line 6 @@\n city: Vigo\n zip: 36200\n country: Spain\n-phone: 555\n+phone: 556\n", stdout.String())
+	})
+
+	t.Run("should approve some hunks", func(t *testing.T) {
+		setUp(t)
+
+		code := run("", "approve", "-hunks", "2", "testdata/TestA.snap")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "approved 1 of 2 hunks of testdata/TestA.snap\n", stdout.String())
+		vfs.AssertContentWasStored(t, fs, "testdata/TestA.snap", []byte("name: Fran\nage: 30\ncity: Vigo\nzip: 36200\ncountry: Spain\nphone: 556\n"))
+		assertNotExists(t, "testdata/TestA.received.snap")
+	})
+
+	t.Run("should approve all hunks", func(t *testing.T) {
+		setUp(t)
+
+		code := run("", "approve", "-hunks", "1, 2,1", "testdata/TestA.snap")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "approved 2 of 2 hunks of testdata/TestA.snap\n", stdout.String())
+		vfs.AssertContentWasStored(t, fs, "testdata/TestA.snap", []byte(received))
+	})
+
+	t.Run("should refuse hunks out of range", func(t *testing.T) {
+		setUp(t)
+
+		code := run("", "approve", "-hunks", "3", "testdata/TestA.snap")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr.String(), `bad hunk "3", testdata/TestA.snap has 2 hunks`)
+		vfs.AssertContentWasStored(t, fs, "testdata/TestA.snap", []byte(snapshot))
+	})
+
+	t.Run("should approve hunks of a single snapshot", func(t *testing.T) {
+		setUp(t)
+
+		code := run("", "approve", "-hunks", "1", "-all")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr.String(), "-hunks needs exactly one snapshot, 2 selected")
+	})
+
+	t.Run("should review hunks interactively", func(t *testing.T) {
+		setUp(t)
+
+		code := run("h\ny\nn\n", "review", "-no-color", "testdata/TestA.snap")
+		assert.Equal(t, 0, code)
+		assert.Contains(t, stdout.String(), "[2/2] @@ line 6 @@\n")
+		assert.Equal(t, 2, strings.Count(stdout.String(), hunkPrompt))
+		vfs.AssertContentWasStored(t, fs, "testdata/TestA.snap", []byte("name: Frank\nage: 30\ncity: Vigo\nzip: 36200\ncountry: Spain\nphone: 555\n"))
+		assertNotExists(t, "testdata/TestA.received.snap")
+		assert.Contains(t, stdout.String(), "Reviewed: 1 accepted, 0 rejected, 0 pending\n")
+	})
+
+	t.Run("should keep snapshot if hunk review is not finished", func(t *testing.T) {
+		setUp(t)
+
+		run("h\ny\n", "review", "-no-color", "testdata/TestA.snap")
+		vfs.AssertContentWasStored(t, fs, "testdata/TestA.snap", []byte(snapshot))
+		vfs.AssertContentWasStored(t, fs, "testdata/TestA.received.snap", []byte(received))
+	})
+}
@@ -0,0 +1,297 @@
+/*
+Command golden reviews the snapshots whose verification failed. When a
+verification fails, golden writes the subject next to the snapshot in a
+received file (TestSomething.received.snap). With this tool you can list them,
+see the differences, and approve or reject them without editing the tests.
+
+Usage:
+
+	golden <command> [flags] [pattern ...]
+
+Commands:
+
+	list     list the pending snapshots
+	diff     show the differences between snapshots and received subjects
+	hunks    show the numbered hunks of changes of the pending snapshots
+	approve  replace the snapshots with the received subjects
+	reject   discard the received subjects
+	review   walk through the pending snapshots, accepting or rejecting them
+
+Patterns select snapshots by path, as in path.Match. A pattern matching a
+folder selects all the snapshots inside it:
+
+	golden approve 'testdata/TestInvoice*'
+
+To approve only some of the changes of a snapshot, pass the numbers of the
+hunks, as shown by the hunks command:
+
+	golden approve -hunks 1,3 testdata/TestInvoice/pdf.snap
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/franiglesias/golden"
+	"github.com/franiglesias/golden/internal/vfs"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const usage = `Usage: golden <command> [flags] [pattern ...]
+
+Commands:
+  list     list the pending snapshots
+  diff     show the differences between snapshots and received subjects
+  hunks    show the numbered hunks of changes of the pending snapshots
+  approve  replace the snapshots with the received subjects
+  reject   discard the received subjects
+  review   walk through the pending snapshots, accepting or rejecting them
+
+Run golden <command> -h to see the flags of each command.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], vfs.NewOsFs(), os.Stdin, os.Stdout, os.Stderr))
+}
+
+/*
+command holds the flags and arguments shared by all the commands
+*/
+type command struct {
+	fs       vfs.Vfs
+	stdin    io.Reader
+	stdout   io.Writer
+	stderr   io.Writer
+	dir      string
+	reporter string
+	noColor  bool
+	all      bool
+	hunks    string
+	patterns []string
+}
+
+/*
+run executes the command line and returns the exit code: 0 on success, 1 on
+errors and 2 on bad usage
+*/
+func run(args []string, fs vfs.Vfs, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	actions := map[string]func(c command) error{
+		"list":    list,
+		"diff":    diff,
+		"hunks":   hunks,
+		"approve": approve,
+		"reject":  reject,
+		"review":  review,
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		_, _ = fmt.Fprint(stdout, usage)
+		return 0
+	}
+	action, ok := actions[name]
+	if !ok {
+		_, _ = fmt.Fprintf(stderr, "golden: unknown command %s\n\n%s", name, usage)
+		return 2
+	}
+
+	c := command{fs: fs, stdin: stdin, stdout: stdout, stderr: stderr}
+	flags := flag.NewFlagSet("golden "+name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&c.dir, "dir", ".", "folder to look for received files")
+	switch name {
+	case "diff":
+		flags.StringVar(&c.reporter, "reporter", "line", "reporter for differences: line, char or better")
+		flags.BoolVar(&c.noColor, "no-color", false, "don't use colors in better reporter")
+	case "review":
+		flags.BoolVar(&c.noColor, "no-color", false, "don't use colors in differences")
+	case "approve":
+		flags.BoolVar(&c.all, "all", false, "select all the pending snapshots")
+		flags.StringVar(&c.hunks, "hunks", "", "comma separated `numbers` of the hunks to approve, in a single snapshot")
+	case "reject":
+		flags.BoolVar(&c.all, "all", false, "select all the pending snapshots")
+	}
+	err := flags.Parse(args[1:])
+	if err != nil {
+		return 2
+	}
+	c.patterns = flags.Args()
+
+	err = action(c)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "golden: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+/*
+selected returns the pending snapshots selected by the patterns. Commands that
+change files require patterns or the -all flag.
+*/
+func (c command) selected(required bool) ([]pending, error) {
+	if required && len(c.patterns) == 0 && !c.all {
+		return nil, fmt.Errorf("no snapshots selected, pass patterns or -all")
+	}
+	all, err := findPending(c.fs, []string{c.dir})
+	if err != nil {
+		return nil, err
+	}
+	selected, err := selectPending(all, c.patterns)
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) == 0 && len(c.patterns) > 0 {
+		return nil, fmt.Errorf("no pending snapshots match %v", c.patterns)
+	}
+	return selected, nil
+}
+
+func list(c command) error {
+	selected, err := c.selected(false)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		_, _ = fmt.Fprintln(c.stdout, "No pending snapshots.")
+		return nil
+	}
+	for _, p := range selected {
+		isNew, err := p.isNew(c.fs)
+		if err != nil {
+			return err
+		}
+		status := "changed"
+		if isNew {
+			status = "new"
+		}
+		_, _ = fmt.Fprintf(c.stdout, "%-8s %s\n", status, p.snapshot)
+	}
+	return nil
+}
+
+func diff(c command) error {
+	reporter, err := c.diffReporter()
+	if err != nil {
+		return err
+	}
+	selected, err := c.selected(false)
+	if err != nil {
+		return err
+	}
+	for _, p := range selected {
+		snapshot, received, err := p.read(c.fs)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(c.stdout, "=== %s\n%s\n", p.snapshot, reporter.Differences(snapshot, received))
+	}
+	return nil
+}
+
+func (c command) diffReporter() (golden.DiffReporter, error) {
+	switch c.reporter {
+	case "line":
+		return golden.NewLineDiffReporter(), nil
+	case "char":
+		return golden.NewCharDiffReporter(), nil
+	case "better":
+		if c.noColor {
+			return golden.NewBetterDiffReporterWithoutColor(), nil
+		}
+		return golden.NewBetterDiffReporter(), nil
+	}
+	return nil, fmt.Errorf("unknown reporter %s", c.reporter)
+}
+
+func hunks(c command) error {
+	selected, err := c.selected(false)
+	if err != nil {
+		return err
+	}
+	for _, p := range selected {
+		d, err := p.diff(c.fs)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(c.stdout, "=== %s\n", p.snapshot)
+		for i, h := range d.Hunks() {
+			_, _ = fmt.Fprintf(c.stdout, "[%d] %s", i+1, h)
+		}
+	}
+	return nil
+}
+
+func approve(c command) error {
+	selected, err := c.selected(true)
+	if err != nil {
+		return err
+	}
+	if c.hunks != "" {
+		return approveHunks(c, selected)
+	}
+	for _, p := range selected {
+		err = p.approve(c.fs)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(c.stdout, "approved %s\n", p.snapshot)
+	}
+	return nil
+}
+
+func reject(c command) error {
+	selected, err := c.selected(true)
+	if err != nil {
+		return err
+	}
+	for _, p := range selected {
+		err = p.reject(c.fs)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(c.stdout, "rejected %s\n", p.snapshot)
+	}
+	return nil
+}
+
+/*
+approveHunks approves some hunks of a single snapshot. Hunks are numbered from
+1, like in the output of the hunks command.
+*/
+func approveHunks(c command, selected []pending) error {
+	if len(selected) != 1 {
+		return fmt.Errorf("-hunks needs exactly one snapshot, %d selected", len(selected))
+	}
+	p := selected[0]
+	d, err := p.diff(c.fs)
+	if err != nil {
+		return err
+	}
+	var accepted []int
+	seen := make(map[int]bool)
+	for _, field := range strings.Split(c.hunks, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || n < 1 || n > d.Len() {
+			return fmt.Errorf("bad hunk %q, %s has %d hunks", field, p.snapshot, d.Len())
+		}
+		if !seen[n] {
+			seen[n] = true
+			accepted = append(accepted, n-1)
+		}
+	}
+	err = p.approveHunks(c.fs, accepted)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(c.stdout, "approved %d of %d hunks of %s\n", len(accepted), d.Len(), p.snapshot)
+	return nil
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"github.com/franiglesias/golden/internal/vfs"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestGoldenCommand(t *testing.T) {
+	var fs *vfs.MemFs
+	var stdout, stderr *bytes.Buffer
+
+	setUp := func(t *testing.T) {
+		fs = vfs.NewMemFs()
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+		files := map[string]string{
+			"testdata/TestInvoice/pdf.snap":               "total: 100",
+			"testdata/TestInvoice/pdf.received.snap":      "total: 120",
+			"testdata/TestInvoice/html.received.snap":     "<p>total: 120</p>",
+			"testdata/TestCustomer.snap.json":             `{"name": "Fran"}`,
+			"testdata/TestCustomer.received.snap.json":    `{"name": "Frank"}`,
+			"testdata/TestUnchanged.snap":                 "nothing to review",
+			"other/TestElsewhere.snap":                    "old",
+			"other/TestElsewhere.received.snap":           "new",
+			"testdata/TestInvoice/pdf_received_data.snap": "not a received file",
+		}
+		for name, content := range files {
+			err := fs.WriteFile(name, []byte(content))
+			assert.NoError(t, err)
+		}
+	}
+
+	run := func(args ...string) int {
+		return run(args, fs, strings.NewReader(""), stdout, stderr)
+	}
+
+	assertNotExists := func(t *testing.T, name string) {
+		exists, err := fs.Exists(name)
+		assert.NoError(t, err)
+		assert.Falsef(t, exists, "%s should not exist", name)
+	}
+
+	t.Run("should list pending snapshots", func(t *testing.T) {
+		setUp(t)
+
+		code := run("list")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "changed  other/TestElsewhere.snap\n"+
+			"changed  testdata/TestCustomer.snap.json\n"+
+			"new      testdata/TestInvoice/html.snap\n"+
+			"changed  testdata/TestInvoice/pdf.snap\n", stdout.String())
+	})
+
+	t.Run("should list pending snapshots in folder", func(t *testing.T) {
+		setUp(t)
+
+		code := run("list", "-dir", "other")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "changed  other/TestElsewhere.snap\n", stdout.String())
+	})
+
+	t.Run("should list pending snapshots matching patterns", func(t *testing.T) {
+		setUp(t)
+
+		code := run("list", "testdata/TestInvoice*")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "new      testdata/TestInvoice/html.snap\n"+
+			"changed  testdata/TestInvoice/pdf.snap\n", stdout.String())
+	})
+
+	t.Run("should tell when nothing is pending", func(t *testing.T) {
+		setUp(t)
+
+		code := run("list", "-dir", "nowhere")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "No pending snapshots.\n", stdout.String())
+	})
+
+	t.Run("should show differences", func(t *testing.T) {
+		setUp(t)
+
+		code := run("diff", "testdata/TestInvoice/pdf.snap")
+		assert.Equal(t, 0, code)
+		assert.Contains(t, stdout.String(), "=== testdata/TestInvoice/pdf.snap\n")
+		assert.Contains(t, stdout.String(), "-total: 100\n+total: 120")
+	})
+
+	t.Run("should show differences with reporter", func(t *testing.T) {
+		setUp(t)
+
+		code := run("diff", "-reporter", "char", "testdata/TestInvoice/pdf.snap")
+		assert.Equal(t, 0, code)
+		assert.Contains(t, stdout.String(), "total: 1(~~0~~)(++2++)0")
+	})
+
+	t.Run("should refuse unknown reporter", func(t *testing.T) {
+		setUp(t)
+
+		code := run("diff", "-reporter", "fancy")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr.String(), "unknown reporter fancy")
+	})
+
+	t.Run("should approve pending snapshot", func(t *testing.T) {
+		setUp(t)
+
+		code := run("approve", "testdata/TestInvoice/pdf.snap")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "approved testdata/TestInvoice/pdf.snap\n", stdout.String())
+		vfs.AssertContentWasStored(t, fs, "testdata/TestInvoice/pdf.snap", []byte("total: 120"))
+		assertNotExists(t, "testdata/TestInvoice/pdf.received.snap")
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/TestInvoice/html.received.snap")
+	})
+
+	t.Run("should approve new snapshot", func(t *testing.T) {
+		setUp(t)
+
+		code := run("approve", "testdata/TestInvoice/html.snap")
+		assert.Equal(t, 0, code)
+		vfs.AssertContentWasStored(t, fs, "testdata/TestInvoice/html.snap", []byte("<p>total: 120</p>"))
+		assertNotExists(t, "testdata/TestInvoice/html.received.snap")
+	})
+
+	t.Run("should approve snapshots matching glob", func(t *testing.T) {
+		setUp(t)
+
+		code := run("approve", "testdata/*.json")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "approved testdata/TestCustomer.snap.json\n", stdout.String())
+		vfs.AssertContentWasStored(t, fs, "testdata/TestCustomer.snap.json", []byte(`{"name": "Frank"}`))
+	})
+
+	t.Run("should approve all snapshots", func(t *testing.T) {
+		setUp(t)
+
+		code := run("approve", "-all")
+		assert.Equal(t, 0, code)
+		vfs.AssertContentWasStored(t, fs, "other/TestElsewhere.snap", []byte("new"))
+		vfs.AssertContentWasStored(t, fs, "testdata/TestInvoice/pdf.snap", []byte("total: 120"))
+		files, err := fs.List(".")
+		assert.NoError(t, err)
+		assert.Len(t, files, 6)
+	})
+
+	t.Run("should require patterns or all to approve", func(t *testing.T) {
+		setUp(t)
+
+		code := run("approve")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr.String(), "no snapshots selected")
+		vfs.AssertContentWasStored(t, fs, "testdata/TestInvoice/pdf.snap", []byte("total: 100"))
+	})
+
+	t.Run("should fail if no snapshot matches", func(t *testing.T) {
+		setUp(t)
+
+		code := run("approve", "testdata/TestNothing*")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr.String(), "no pending snapshots match [testdata/TestNothing*]")
+	})
+
+	t.Run("should reject pending snapshot", func(t *testing.T) {
+		setUp(t)
+
+		code := run("reject", "testdata/TestInvoice/pdf.snap")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "rejected testdata/TestInvoice/pdf.snap\n", stdout.String())
+		vfs.AssertContentWasStored(t, fs, "testdata/TestInvoice/pdf.snap", []byte("total: 100"))
+		assertNotExists(t, "testdata/TestInvoice/pdf.received.snap")
+	})
+
+	t.Run("should report bad patterns", func(t *testing.T) {
+		setUp(t)
+
+		code := run("reject", "testdata/[")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr.String(), "bad pattern testdata/[")
+	})
+
+	t.Run("should show usage", func(t *testing.T) {
+		setUp(t)
+
+		assert.Equal(t, 2, run())
+		assert.Contains(t, stderr.String(), "Usage: golden <command>")
+		assert.Equal(t, 2, run("bless"))
+		assert.Contains(t, stderr.String(), "unknown command bless")
+		assert.Equal(t, 0, run("help"))
+		assert.Contains(t, stdout.String(), "Usage: golden <command>")
+	})
+}
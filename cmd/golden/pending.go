@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/franiglesias/golden/internal/hunk"
+	"github.com/franiglesias/golden/internal/received"
+	"github.com/franiglesias/golden/internal/vfs"
+	"path"
+	"sort"
+)
+
+/*
+pending is a snapshot with a received file written by a failed verification,
+waiting to be approved or rejected
+*/
+type pending struct {
+	snapshot string
+	received string
+}
+
+/*
+findPending looks for received files under the folders
+*/
+func findPending(fs vfs.Vfs, dirs []string) ([]pending, error) {
+	seen := make(map[string]bool)
+	var found []pending
+	for _, dir := range dirs {
+		files, err := fs.List(dir)
+		if err != nil {
+			return nil, fmt.Errorf("could not list %s: %w", dir, err)
+		}
+		for _, file := range files {
+			snapshot, ok := received.Snapshot(file)
+			if !ok || seen[file] {
+				continue
+			}
+			seen[file] = true
+			found = append(found, pending{snapshot: snapshot, received: file})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].snapshot < found[j].snapshot
+	})
+	return found, nil
+}
+
+/*
+selectPending returns the pending snapshots matching any of the patterns. A
+pattern matches if it matches the path of the snapshot, or any of the folders
+containing it, so you can select all the snapshots of a test:
+
+	testdata/TestInvoice*
+*/
+func selectPending(all []pending, patterns []string) ([]pending, error) {
+	if len(patterns) == 0 {
+		return all, nil
+	}
+	var selected []pending
+	for _, p := range all {
+		ok, err := matchAny(patterns, p.snapshot)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			selected = append(selected, p)
+		}
+	}
+	return selected, nil
+}
+
+func matchAny(patterns []string, name string) (bool, error) {
+	for _, pattern := range patterns {
+		for p := name; p != "." && p != "/"; p = path.Dir(p) {
+			ok, err := path.Match(pattern, p)
+			if err != nil {
+				return false, fmt.Errorf("bad pattern %s: %w", pattern, err)
+			}
+			if ok {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+/*
+read returns the current content of the snapshot and the received subject. The
+snapshot is empty if it doesn't exist yet.
+*/
+func (p pending) read(fs vfs.Vfs) (snapshot string, received string, err error) {
+	content, err := fs.ReadFile(p.snapshot)
+	if err != nil && !errors.Is(err, vfs.SnapshotNotFound) {
+		return "", "", fmt.Errorf("could not read snapshot %s: %w", p.snapshot, err)
+	}
+	snapshot = string(content)
+	content, err = fs.ReadFile(p.received)
+	if err != nil {
+		return "", "", fmt.Errorf("could not read received file %s: %w", p.received, err)
+	}
+	return snapshot, string(content), nil
+}
+
+func (p pending) isNew(fs vfs.Vfs) (bool, error) {
+	exists, err := fs.Exists(p.snapshot)
+	return !exists, err
+}
+
+/*
+approve replaces the snapshot with the received subject
+*/
+func (p pending) approve(fs vfs.Vfs) error {
+	content, err := fs.ReadFile(p.received)
+	if err != nil {
+		return fmt.Errorf("could not read received file %s: %w", p.received, err)
+	}
+	err = fs.WriteFile(p.snapshot, content)
+	if err != nil {
+		return fmt.Errorf("could not write snapshot %s: %w", p.snapshot, err)
+	}
+	return p.reject(fs)
+}
+
+/*
+diff returns the hunks of the changes between the snapshot and the received
+subject
+*/
+func (p pending) diff(fs vfs.Vfs) (hunk.Diff, error) {
+	snapshot, received, err := p.read(fs)
+	if err != nil {
+		return hunk.Diff{}, err
+	}
+	return hunk.Compute(snapshot, received), nil
+}
+
+/*
+approveHunks replaces the snapshot with a merge that contains only the accepted
+hunks, starting at 0, and discards the received subject
+*/
+func (p pending) approveHunks(fs vfs.Vfs, accepted []int) error {
+	d, err := p.diff(fs)
+	if err != nil {
+		return err
+	}
+	err = fs.WriteFile(p.snapshot, []byte(d.Merge(accepted)))
+	if err != nil {
+		return fmt.Errorf("could not write snapshot %s: %w", p.snapshot, err)
+	}
+	return p.reject(fs)
+}
+
+/*
+reject discards the received subject, keeping the snapshot as it was
+*/
+func (p pending) reject(fs vfs.Vfs) error {
+	err := fs.Remove(p.received)
+	if err != nil {
+		return fmt.Errorf("could not remove received file %s: %w", p.received, err)
+	}
+	return nil
+}
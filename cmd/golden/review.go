@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/franiglesias/golden"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const reviewPrompt = "(a)ccept, (r)eject, (s)kip, (e)dit, accept (h)unks, (q)uit? "
+const hunkPrompt = "Accept this hunk? (y)es, (n)o? "
+
+/*
+editFile opens the file in the editor of the user, defined by VISUAL or EDITOR,
+and waits for it to exit. The editor reads from the terminal, not from stdin:
+the answers to the next prompts may be buffered already, and the editor must
+not take them.
+*/
+var editFile = func(name string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	tty, err := os.Open(terminal())
+	if err != nil {
+		return fmt.Errorf("could not open the terminal for the editor: %w", err)
+	}
+	defer func() {
+		_ = tty.Close()
+	}()
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], name)...)
+	cmd.Stdin = tty
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func terminal() string {
+	if runtime.GOOS == "windows" {
+		return "CONIN$"
+	}
+	return "/dev/tty"
+}
+
+/*
+review walks through the pending snapshots, showing the differences and asking
+what to do with each one. Answers are read line by line from a single reader,
+so it works in any terminal and can be scripted, but every answer needs Enter.
+Editing opens the received file, so you can fix it before accepting it.
+Accepting hunks asks for each group of changes, and writes a snapshot with only
+the accepted ones.
+*/
+func review(c command) error {
+	selected, err := c.selected(false)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		_, _ = fmt.Fprintln(c.stdout, "No pending snapshots.")
+		return nil
+	}
+
+	var reporter golden.DiffReporter = golden.NewBetterDiffReporter()
+	if c.noColor {
+		reporter = golden.NewBetterDiffReporterWithoutColor()
+	}
+
+	input := bufio.NewScanner(c.stdin)
+	accepted, rejected := 0, 0
+	summary := func() {
+		_, _ = fmt.Fprintf(c.stdout, "Reviewed: %d accepted, %d rejected, %d pending\n", accepted, rejected, len(selected)-accepted-rejected)
+	}
+
+	for i := 0; i < len(selected); i++ {
+		p := selected[i]
+		snapshot, received, err := p.read(c.fs)
+		if err != nil {
+			return err
+		}
+		isNew, err := p.isNew(c.fs)
+		if err != nil {
+			return err
+		}
+		status := "changed"
+		if isNew {
+			status = "new"
+		}
+		_, _ = fmt.Fprintf(c.stdout, "[%d/%d] %s (%s)\n%s\n", i+1, len(selected), p.snapshot, status, reporter.Differences(snapshot, received))
+
+		answer, ok := ask(c, input, reviewPrompt, "a", "r", "s", "e", "h", "q")
+		switch {
+		case !ok, answer == "q":
+			summary()
+			return nil
+		case answer == "a":
+			err = p.approve(c.fs)
+			accepted++
+		case answer == "r":
+			err = p.reject(c.fs)
+			rejected++
+		case answer == "e":
+			err = editFile(p.received)
+			i--
+		case answer == "h":
+			var done bool
+			done, err = reviewHunks(c, input, p)
+			if done {
+				accepted++
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	summary()
+	return nil
+}
+
+/*
+reviewHunks asks for each hunk of the snapshot and approves the accepted ones.
+Returns false if the input is over before answering all of them.
+*/
+func reviewHunks(c command, input *bufio.Scanner, p pending) (bool, error) {
+	d, err := p.diff(c.fs)
+	if err != nil {
+		return false, err
+	}
+	var accepted []int
+	for i, h := range d.Hunks() {
+		_, _ = fmt.Fprintf(c.stdout, "[%d/%d] %s", i+1, d.Len(), h)
+		answer, ok := ask(c, input, hunkPrompt, "y", "n")
+		if !ok {
+			return false, nil
+		}
+		if answer == "y" {
+			accepted = append(accepted, i)
+		}
+	}
+	return true, p.approveHunks(c.fs, accepted)
+}
+
+/*
+ask prompts until it gets one of the options. Returns false if the input is
+over.
+*/
+func ask(c command, input *bufio.Scanner, prompt string, options ...string) (string, bool) {
+	for {
+		_, _ = fmt.Fprint(c.stdout, prompt)
+		if !input.Scan() {
+			_, _ = fmt.Fprintln(c.stdout)
+			return "", false
+		}
+		answer := strings.ToLower(strings.TrimSpace(input.Text()))
+		for _, option := range options {
+			if answer == option {
+				return answer, true
+			}
+		}
+		_, _ = fmt.Fprintf(c.stdout, "Unknown option %q\n", answer)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"github.com/franiglesias/golden/internal/vfs"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestReview(t *testing.T) {
+	var fs *vfs.MemFs
+	var stdout, stderr *bytes.Buffer
+
+	setUp := func(t *testing.T) {
+		fs = vfs.NewMemFs()
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+		files := map[string]string{
+			"testdata/TestA.snap":          "a: old",
+			"testdata/TestA.received.snap": "a: new",
+			"testdata/TestB.snap":          "b: old",
+			"testdata/TestB.received.snap": "b: new",
+			"testdata/TestC.received.snap": "c: new",
+		}
+		for name, content := range files {
+			err := fs.WriteFile(name, []byte(content))
+			assert.NoError(t, err)
+		}
+	}
+
+	review := func(keys string, args ...string) int {
+		args = append([]string{"review", "-no-color"}, args...)
+		return run(args, fs, strings.NewReader(keys), stdout, stderr)
+	}
+
+	assertNotExists := func(t *testing.T, name string) {
+		exists, err := fs.Exists(name)
+		assert.NoError(t, err)
+		assert.Falsef(t, exists, "%s should not exist", name)
+	}
+
+	t.Run("should accept, reject and skip snapshots", func(t *testing.T) {
+		setUp(t)
+
+		code := review("a\nr\ns\n")
+		assert.Equal(t, 0, code)
+		vfs.AssertContentWasStored(t, fs, "testdata/TestA.snap", []byte("a: new"))
+		assertNotExists(t, "testdata/TestA.received.snap")
+		vfs.AssertContentWasStored(t, fs, "testdata/TestB.snap", []byte("b: old"))
+		assertNotExists(t, "testdata/TestB.received.snap")
+		assertNotExists(t, "testdata/TestC.snap")
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/TestC.received.snap")
+		assert.Contains(t, stdout.String(), "Reviewed: 1 accepted, 1 rejected, 1 pending\n")
+	})
+
+	t.Run("should show differences of each snapshot", func(t *testing.T) {
+		setUp(t)
+
+		review("s\ns\ns\n")
+		output := stdout.String()
+		assert.Contains(t, output, "[1/3] testdata/TestA.snap (changed)\n")
+		assert.Contains(t, output, "[3/3] testdata/TestC.snap (new)\n")
+		assert.Contains(t, output, "a: new")
+		assert.Equal(t, 3, strings.Count(output, reviewPrompt))
+	})
+
+	t.Run("should show existing empty snapshots as changed", func(t *testing.T) {
+		setUp(t)
+		err := fs.WriteFile("testdata/TestC.snap", []byte(""))
+		assert.NoError(t, err)
+
+		review("s\n", "testdata/TestC.snap")
+		assert.Contains(t, stdout.String(), "[1/1] testdata/TestC.snap (changed)\n")
+	})
+
+	t.Run("should review snapshots matching patterns", func(t *testing.T) {
+		setUp(t)
+
+		review("a\n", "testdata/TestC.snap")
+		vfs.AssertContentWasStored(t, fs, "testdata/TestC.snap", []byte("c: new"))
+		assert.Contains(t, stdout.String(), "[1/1] testdata/TestC.snap (new)\n")
+	})
+
+	t.Run("should stop on quit", func(t *testing.T) {
+		setUp(t)
+
+		review("a\nq\na\n")
+		vfs.AssertContentWasStored(t, fs, "testdata/TestB.snap", []byte("b: old"))
+		assert.Contains(t, stdout.String(), "Reviewed: 1 accepted, 0 rejected, 2 pending\n")
+	})
+
+	t.Run("should stop at end of input", func(t *testing.T) {
+		setUp(t)
+
+		code := review("r\n")
+		assert.Equal(t, 0, code)
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/TestB.received.snap")
+		assert.Contains(t, stdout.String(), "Reviewed: 0 accepted, 1 rejected, 2 pending\n")
+	})
+
+	t.Run("should ask again on unknown options", func(t *testing.T) {
+		setUp(t)
+
+		review("yes\nA\n", "testdata/TestA.snap")
+		assert.Contains(t, stdout.String(), "Unknown option \"yes\"\n")
+		vfs.AssertContentWasStored(t, fs, "testdata/TestA.snap", []byte("a: new"))
+	})
+
+	t.Run("should edit received subject before accepting it", func(t *testing.T) {
+		setUp(t)
+		defer func(previous func(string) error) { editFile = previous }(editFile)
+		var edited string
+		editFile = func(name string) error {
+			edited = name
+			return fs.WriteFile(name, []byte("a: edited"))
+		}
+
+		review("e\na\n", "testdata/TestA.snap")
+		assert.Equal(t, "testdata/TestA.received.snap", edited)
+		assert.Contains(t, stdout.String(), "a: edited")
+		vfs.AssertContentWasStored(t, fs, "testdata/TestA.snap", []byte("a: edited"))
+	})
+
+	t.Run("should fail if editor fails", func(t *testing.T) {
+		setUp(t)
+		defer func(previous func(string) error) { editFile = previous }(editFile)
+		editFile = func(name string) error {
+			return errors.New("editor not found")
+		}
+
+		code := review("e\n")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr.String(), "editor not found")
+	})
+
+	t.Run("should tell when nothing is pending", func(t *testing.T) {
+		setUp(t)
+
+		code := review("", "-dir", "nowhere")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "No pending snapshots.\n", stdout.String())
+	})
+}
@@ -1,19 +1,40 @@
 package golden
 
-import "path"
+import (
+	"fmt"
+	"path"
+	"strings"
+)
 
 type Config struct {
 	folder    string
 	name      string
 	ext       string
+	index     int
+	layout    SnapshotLayout
+	template  string
+	format    string
+	source    sourceLocation
 	approve   bool
+	strict    bool
+	strictCI  bool
+	readOnly  bool
 	reporter  DiffReporter
 	scrubbers []Scrubber
 }
 
 func (c Config) snapshotPath(t Failable) string {
-	if c.name == "" {
-		c.name = t.Name()
+	if c.name != "" {
+		return path.Join(c.folder, c.name+c.ext)
+	}
+
+	if c.template != "" {
+		return path.Join(c.folder, c.resolveTemplate(t))
+	}
+
+	c.name = c.snapshotLayout().Path(c.source.pkg(), t.Name())
+	if c.index > 1 {
+		c.name = fmt.Sprintf("%s_%d", c.name, c.index)
 	}
 
 	return path.Join(c.folder, c.name+c.ext)
@@ -22,3 +43,69 @@ func (c Config) snapshotPath(t Failable) string {
 func (c Config) approvalMode() bool {
 	return c.approve
 }
+
+/*
+differences reports the differences with the configured reporter, passing the
+context of the verification if the reporter accepts it
+*/
+func (c Config) differences(t Failable, mode string, path string, want, got string) string {
+	ctx := ReportContext{
+		Path:      path,
+		TestName:  t.Name(),
+		Mode:      mode,
+		Format:    c.format,
+		Scrubbers: c.scrubbers,
+	}
+	return reportDifferences(c.reporter, ctx, want, got)
+}
+
+func reportDifferences(reporter DiffReporter, ctx ReportContext, want, got string) string {
+	return AdaptReporter(reporter).ContextDifferences(ctx, want, got)
+}
+
+func (c Config) snapshotLayout() SnapshotLayout {
+	if c.layout == nil {
+		return NestedLayout{}
+	}
+	return c.layout
+}
+
+/*
+needsSource returns true if the snapshot path depends on the test source: the
+package or file placeholders of a template, or layouts that may use the package
+*/
+func (c Config) needsSource() bool {
+	if c.template != "" {
+		return strings.Contains(c.template, "{package}") || strings.Contains(c.template, "{file}")
+	}
+	switch c.snapshotLayout().(type) {
+	case NestedLayout, FlatLayout, TopLevelLayout:
+		return false
+	}
+	return true
+}
+
+func (c Config) readOnlyMode() bool {
+	return c.readOnly || readOnlyRequested()
+}
+
+func (c Config) strictMode() bool {
+	return c.strict || strictRequested() || (c.strictCI && ciRequested())
+}
+
+/*
+strictRefusal returns the message to stop the test if it tries to write the
+snapshot in strict mode, or an empty string
+*/
+func (c Config) strictRefusal() string {
+	if !c.strictMode() {
+		return ""
+	}
+	if c.approvalMode() {
+		return strictApproval
+	}
+	if updateRequested() {
+		return strictUpdate
+	}
+	return ""
+}
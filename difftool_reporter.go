@@ -0,0 +1,246 @@
+package golden
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const diffToolEnv = "GOLDEN_DIFFTOOL"
+const diffToolSent = "Differences sent to %s"
+const diffToolFailed = "could not launch diff tool: %s"
+
+/*
+DiffToolReporter opens the differences in an external tool, defined by the
+GOLDEN_DIFFTOOL environment variable. Snapshot and subject are written to
+temporary files, that replace the {want} and {got} placeholders in the command.
+Without placeholders, both files are added at the end:
+
+	GOLDEN_DIFFTOOL="meld {want} {got}"
+	GOLDEN_DIFFTOOL="code --wait --diff"
+	GOLDEN_DIFFTOOL="vimdiff"
+
+The tool is not launched in CI (CI=true) or in terminals that are not
+interactive. The text of the wrapped reporter is returned anyway, so you also
+get it in the test output.
+
+Verify launches the tool after releasing the snapshot, so other tests don't
+wait for it, and the test waits for the tool to exit. Temporary files are
+removed then, so the command must not return before the diff is closed, like
+code without --wait does. The tool is attached to the terminal, not to the
+standard input and output of the test, so terminal tools like vimdiff work.
+*/
+type DiffToolReporter struct {
+	reporter DiffReporter
+}
+
+/*
+NewDiffToolReporter launches the diff tool and returns the text of reporter. If
+reporter is nil, it only returns a short message, unless the tool is disabled.
+*/
+func NewDiffToolReporter(reporter DiffReporter) DiffToolReporter {
+	return DiffToolReporter{
+		reporter: reporter,
+	}
+}
+
+func (d DiffToolReporter) Differences(want, got string) string {
+	return d.ContextDifferences(ReportContext{}, want, got)
+}
+
+func (d DiffToolReporter) ContextDifferences(ctx ReportContext, want, got string) string {
+	tool := os.Getenv(diffToolEnv)
+	if want == got || tool == "" || !interactive() {
+		return reportDifferences(d.textReporter(), ctx, want, got)
+	}
+
+	text := fmt.Sprintf(diffToolSent, strings.Fields(tool)[0])
+	if d.reporter != nil {
+		text = reportDifferences(d.reporter, ctx, want, got)
+	}
+	launch := diffToolLaunch{tool: tool, snapshot: ctx.Path, want: want, got: got}
+	if diffTools.add(ctx.TestName, launch) {
+		return text
+	}
+	err := launch.run()
+	if err != nil {
+		text += "\n" + fmt.Sprintf(diffToolFailed, err)
+	}
+	return text
+}
+
+func (d DiffToolReporter) textReporter() DiffReporter {
+	if d.reporter == nil {
+		return LineDiffReporter{}
+	}
+	return d.reporter
+}
+
+/*
+interactive returns false in CI, or if the terminal can't be used by a person
+*/
+func interactive() bool {
+	if ciRequested() {
+		return false
+	}
+	term := os.Getenv("TERM")
+	return term != "" && term != "dumb"
+}
+
+/*
+diffToolQueue holds, by test name, the diff tools requested while Verify keeps
+the snapshot locked, to launch them after it is released
+*/
+type diffToolQueue struct {
+	sync.Mutex
+	holding map[string]int
+	pending map[string][]diffToolLaunch
+}
+
+var diffTools = &diffToolQueue{
+	holding: make(map[string]int),
+	pending: make(map[string][]diffToolLaunch),
+}
+
+/*
+hold keeps the tools requested by the test until the returned function is
+called. It launches them, logging the failures, because the report of the test
+was already written.
+*/
+func (q *diffToolQueue) hold(t Failable) func() {
+	name := t.Name()
+	q.Lock()
+	q.holding[name]++
+	q.Unlock()
+
+	return func() {
+		q.Lock()
+		q.holding[name]--
+		if q.holding[name] == 0 {
+			delete(q.holding, name)
+		}
+		launches := q.pending[name]
+		delete(q.pending, name)
+		q.Unlock()
+
+		for _, launch := range launches {
+			if err := launch.run(); err != nil {
+				logf(t, diffToolFailed, err)
+			}
+		}
+	}
+}
+
+/*
+add queues the launch if the test is held, and returns false otherwise
+*/
+func (q *diffToolQueue) add(test string, launch diffToolLaunch) bool {
+	q.Lock()
+	defer q.Unlock()
+	if q.holding[test] == 0 {
+		return false
+	}
+	q.pending[test] = append(q.pending[test], launch)
+	return true
+}
+
+type diffToolLaunch struct {
+	tool     string
+	snapshot string
+	want     string
+	got      string
+}
+
+/*
+run writes snapshot and subject to temporary files and runs the tool, waiting
+for it to exit. The tool uses the terminal, because go test doesn't give its
+input to the tests. Without a terminal, the output of the tool is only shown if
+it fails.
+*/
+func (l diffToolLaunch) run() error {
+	dir, err := os.MkdirTemp("", "golden-difftool-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	base := path.Base(l.snapshot)
+	if l.snapshot == "" || base == "." || base == "/" {
+		base = "snapshot"
+	}
+	wantFile := filepath.Join(dir, "want_"+base)
+	gotFile := filepath.Join(dir, "got_"+base)
+	err = os.WriteFile(wantFile, []byte(l.want), 0644)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(gotFile, []byte(l.got), 0644)
+	if err != nil {
+		return err
+	}
+
+	args := diffToolArgs(l.tool, wantFile, gotFile)
+	cmd := exec.Command(args[0], args[1:]...)
+	input, output, err := openTerminal()
+	if err != nil {
+		combined, err := cmd.CombinedOutput()
+		if err != nil && len(combined) > 0 {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(combined)))
+		}
+		return err
+	}
+	defer func() {
+		_ = input.Close()
+		_ = output.Close()
+	}()
+	cmd.Stdin = input
+	cmd.Stdout = output
+	cmd.Stderr = output
+	return cmd.Run()
+}
+
+/*
+openTerminal opens the terminal of the user for input and output, like the
+editor of the golden command does
+*/
+func openTerminal() (*os.File, *os.File, error) {
+	in, out := "/dev/tty", "/dev/tty"
+	if runtime.GOOS == "windows" {
+		in, out = "CONIN$", "CONOUT$"
+	}
+	input, err := os.Open(in)
+	if err != nil {
+		return nil, nil, err
+	}
+	output, err := os.OpenFile(out, os.O_WRONLY, 0)
+	if err != nil {
+		_ = input.Close()
+		return nil, nil, err
+	}
+	return input, output, nil
+}
+
+/*
+diffToolArgs replaces the placeholders in the command, or adds the files at the
+end. Arguments are separated by spaces.
+*/
+func diffToolArgs(tool string, wantFile, gotFile string) []string {
+	fields := strings.Fields(tool)
+	placeholders := false
+	for i, field := range fields {
+		replaced := strings.NewReplacer("{want}", wantFile, "{got}", gotFile).Replace(field)
+		if replaced != field {
+			placeholders = true
+		}
+		fields[i] = replaced
+	}
+	if !placeholders {
+		fields = append(fields, wantFile, gotFile)
+	}
+	return fields
+}
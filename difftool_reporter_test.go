@@ -0,0 +1,189 @@
+package golden_test
+
+import (
+	"github.com/franiglesias/golden"
+	"github.com/franiglesias/golden/internal/helper"
+	"github.com/franiglesias/golden/internal/vfs"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+/*
+TestDiffToolReporter uses a shell script that stands in for the diff tool. It
+copies the files it receives, so we can check them.
+*/
+func TestDiffToolReporter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as diff tool")
+	}
+
+	var out string
+
+	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
+		dir := t.TempDir()
+		out = filepath.Join(dir, "out")
+		script := filepath.Join(dir, "difftool")
+		err := os.WriteFile(script, []byte("#!/bin/sh\ncat \"$1\" > "+out+".want\ncat \"$2\" > "+out+".got\necho \"$1\" > "+out+".args\n"), 0755)
+		assert.NoError(t, err)
+		t.Setenv("GOLDEN_DIFFTOOL", script)
+		t.Setenv("TERM", "xterm")
+		t.Setenv("TMPDIR", dir)
+	}
+
+	assertToolReceived := func(t *testing.T, want, got string) {
+		content, err := os.ReadFile(out + ".want")
+		assert.NoError(t, err)
+		assert.Equal(t, want, string(content))
+		content, err = os.ReadFile(out + ".got")
+		assert.NoError(t, err)
+		assert.Equal(t, got, string(content))
+	}
+
+	assertToolNotLaunched := func(t *testing.T) {
+		_, err := os.Stat(out + ".want")
+		assert.True(t, os.IsNotExist(err), "diff tool was launched")
+	}
+
+	t.Run("should launch tool and return text", func(t *testing.T) {
+		setUp(t)
+		reporter := golden.NewDiffToolReporter(golden.NewLineDiffReporter())
+
+		result := reporter.Differences("Wanted this.", "Gotten that.")
+		assert.Contains(t, result, "-Wanted this.")
+		assertToolReceived(t, "Wanted this.", "Gotten that.")
+	})
+
+	t.Run("should only tell that tool was launched without reporter", func(t *testing.T) {
+		setUp(t)
+		reporter := golden.NewDiffToolReporter(nil)
+
+		result := reporter.Differences("Wanted this.", "Gotten that.")
+		assert.Contains(t, result, "Differences sent to ")
+		assert.NotContains(t, result, "Wanted this.")
+		assertToolReceived(t, "Wanted this.", "Gotten that.")
+	})
+
+	t.Run("should replace placeholders", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_DIFFTOOL", os.Getenv("GOLDEN_DIFFTOOL")+" {got} {want}")
+		reporter := golden.NewDiffToolReporter(nil)
+
+		reporter.Differences("Wanted this.", "Gotten that.")
+		assertToolReceived(t, "Gotten that.", "Wanted this.")
+	})
+
+	t.Run("should name files after snapshot", func(t *testing.T) {
+		setUp(t)
+		reporter := golden.NewDiffToolReporter(nil)
+
+		reporter.ContextDifferences(golden.ReportContext{Path: "testdata/TestSomething.snap"}, "Wanted this.", "Gotten that.")
+		content, err := os.ReadFile(out + ".args")
+		assert.NoError(t, err)
+		assert.Equal(t, "want_TestSomething.snap\n", filepath.Base(string(content)))
+	})
+
+	t.Run("should not launch tool without differences", func(t *testing.T) {
+		setUp(t)
+		reporter := golden.NewDiffToolReporter(nil)
+
+		result := reporter.Differences("Same content", "Same content")
+		assert.Equal(t, "No differences found.", result)
+		assertToolNotLaunched(t)
+	})
+
+	t.Run("should not launch tool in CI", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("CI", "true")
+		reporter := golden.NewDiffToolReporter(nil)
+
+		result := reporter.Differences("Wanted this.", "Gotten that.")
+		assert.Contains(t, result, "-Wanted this.")
+		assertToolNotLaunched(t)
+	})
+
+	t.Run("should not launch tool in non interactive terminal", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("TERM", "dumb")
+		reporter := golden.NewDiffToolReporter(nil)
+
+		reporter.Differences("Wanted this.", "Gotten that.")
+		assertToolNotLaunched(t)
+	})
+
+	t.Run("should remove temporary files", func(t *testing.T) {
+		setUp(t)
+		reporter := golden.NewDiffToolReporter(nil)
+
+		reporter.Differences("Wanted this.", "Gotten that.")
+		temp, err := filepath.Glob(filepath.Join(os.Getenv("TMPDIR"), "golden-difftool-*"))
+		assert.NoError(t, err)
+		assert.Empty(t, temp)
+	})
+
+	t.Run("should launch tool from Verify after releasing the snapshot", func(t *testing.T) {
+		setUp(t)
+		// the tool waits until the snapshot is verified again, or 5 seconds if it is locked
+		blocking := filepath.Join(filepath.Dir(out), "blocking")
+		err := os.WriteFile(blocking, []byte("#!/bin/sh\n"+os.Getenv("GOLDEN_DIFFTOOL")+" \"$1\" \"$2\"\ni=0\nwhile [ ! -f "+out+".release ] && [ $i -lt 500 ]; do sleep 0.01; i=$((i+1)); done\n[ -f "+out+".release ] || touch "+out+".timeout\n"), 0755)
+		assert.NoError(t, err)
+		t.Setenv("GOLDEN_DIFFTOOL", blocking)
+
+		fs := vfs.NewMemFs()
+		gld := golden.NewUsingFs(fs)
+		err = fs.WriteFile("testdata/difftool.snap", []byte("Wanted this."))
+		assert.NoError(t, err)
+
+		tSpy := helper.TSpy{T: t}
+		done := make(chan bool)
+		go func() {
+			gld.Verify(&tSpy, "Gotten that.", golden.Snapshot("difftool"), golden.Reporter(golden.NewDiffToolReporter(nil)))
+			done <- true
+		}()
+		assert.Eventually(t, func() bool {
+			_, err := os.Stat(out + ".got")
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+
+		other := helper.TSpy{T: t}
+		gld.Verify(&other, "Wanted this.", golden.Snapshot("difftool"))
+		err = os.WriteFile(out+".release", nil, 0644)
+		assert.NoError(t, err)
+		<-done
+
+		_, err = os.Stat(out + ".timeout")
+		assert.True(t, os.IsNotExist(err), "diff tool was launched with the snapshot locked")
+		helper.AssertPassTest(t, &other)
+		helper.AssertFailedTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "Differences sent to ")
+		assertToolReceived(t, "Wanted this.", "Gotten that.")
+	})
+
+	t.Run("should log tool failures from Verify", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_DIFFTOOL", filepath.Join(t.TempDir(), "missing-tool"))
+		fs := vfs.NewMemFs()
+		gld := golden.NewUsingFs(fs)
+		err := fs.WriteFile("testdata/difftool.snap", []byte("Wanted this."))
+		assert.NoError(t, err)
+		tSpy := helper.TSpy{T: t}
+
+		gld.Verify(&tSpy, "Gotten that.", golden.Snapshot("difftool"), golden.Reporter(golden.NewDiffToolReporter(nil)))
+		helper.AssertFailedTest(t, &tSpy)
+		helper.AssertLogContains(t, &tSpy, "could not launch diff tool:")
+	})
+
+	t.Run("should report tool failures", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_DIFFTOOL", filepath.Join(t.TempDir(), "missing-tool"))
+		reporter := golden.NewDiffToolReporter(golden.NewLineDiffReporter())
+
+		result := reporter.Differences("Wanted this.", "Gotten that.")
+		assert.Contains(t, result, "-Wanted this.")
+		assert.Contains(t, result, "could not launch diff tool:")
+	})
+}
@@ -15,6 +15,7 @@ func TestGlobalApproval(t *testing.T) {
 	var tSpy helper.TSpy
 
 	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
 		fs = vfs.NewMemFs()
 		golden.G = golden.NewUsingFs(fs)
 		tSpy = helper.TSpy{
@@ -22,6 +23,13 @@ func TestGlobalApproval(t *testing.T) {
 		}
 	}
 
+	// nextRun simulates running the test again: a new Golden instance that keeps
+	// the snapshots written in previous runs
+
+	nextRun := func() {
+		golden.G = golden.NewUsingFs(fs)
+	}
+
 	t.Run("should create snapshot and fail", func(t *testing.T) {
 		setUp(t)
 
@@ -43,6 +51,7 @@ func TestGlobalApproval(t *testing.T) {
 		vfs.AssertContentWasStored(t, fs, "testdata/TestGlobalApproval/should_keep_test_failing_while_approval_mode.snap", []byte("starting subject."))
 		tSpy.Reset()
 
+		nextRun()
 		golden.Verify(&tSpy, "updated subject.", golden.WaitApproval())
 		helper.AssertFailedTest(t, &tSpy)
 		vfs.AssertContentWasStored(t, fs, "testdata/TestGlobalApproval/should_keep_test_failing_while_approval_mode.snap", []byte("updated subject."))
@@ -61,12 +70,14 @@ func TestGlobalApproval(t *testing.T) {
 
 		// After this run the snapshot will be approved by an expert
 
+		nextRun()
 		golden.Verify(&tSpy, "updated subject.", golden.WaitApproval())
 		tSpy.Reset()
 
 		// At this point, the snapshot was approved, so we can change the test back to
 		// Verification mode, removing the golden.WaitApproval() option
 
+		nextRun()
 		golden.Verify(&tSpy, "updated subject.")
 		helper.AssertPassTest(t, &tSpy)
 	})
@@ -106,6 +117,7 @@ func TestGlobalApproval(t *testing.T) {
 		golden.Verify(&tSpy, "original output.", golden.WaitApproval())
 
 		// Changes happened. Verify against existing snapshot
+		nextRun()
 		golden.Verify(&tSpy, "different output.", golden.WaitApproval())
 
 		helper.AssertFailedTest(t, &tSpy)
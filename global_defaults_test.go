@@ -2,6 +2,7 @@ package golden_test
 
 import (
 	"github.com/franiglesias/golden"
+	"github.com/franiglesias/golden/internal/helper"
 	"github.com/franiglesias/golden/internal/vfs"
 	"testing"
 )
@@ -14,6 +15,7 @@ func TestGlobalDefaults(t *testing.T) {
 	var fs *vfs.MemFs
 
 	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
 		// Passing t in each setup guarantees that we are using the right name for the
 		// snapshot, otherwise the name won't be accurate
 
@@ -15,6 +15,7 @@ func TestGlobalMaster(t *testing.T) {
 	var tSpy helper.TSpy
 
 	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
 		fs = vfs.NewMemFs()
 		golden.G = golden.NewUsingFs(fs)
 		tSpy = helper.TSpy{
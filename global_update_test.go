@@ -0,0 +1,61 @@
+package golden_test
+
+import (
+	"github.com/franiglesias/golden"
+	"github.com/franiglesias/golden/internal/helper"
+	"github.com/franiglesias/golden/internal/vfs"
+	"testing"
+)
+
+func TestGlobalUpdate(t *testing.T) {
+	var fs *vfs.MemFs
+	var tSpy helper.TSpy
+
+	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
+		t.Setenv("GOLDEN_UPDATE", "true")
+		fs = vfs.NewMemFs()
+		golden.G = golden.NewUsingFs(fs)
+		tSpy = helper.TSpy{
+			T: t,
+		}
+	}
+
+	t.Run("should create snapshot and pass", func(t *testing.T) {
+		setUp(t)
+
+		golden.Verify(&tSpy, "some subject.")
+		vfs.AssertContentWasStored(t, fs, "testdata/TestGlobalUpdate/should_create_snapshot_and_pass.snap", []byte("some subject."))
+		helper.AssertPassTest(t, &tSpy)
+	})
+
+	t.Run("should rewrite outdated snapshot and pass", func(t *testing.T) {
+		setUp(t)
+
+		err := fs.WriteFile("testdata/TestGlobalUpdate/should_rewrite_outdated_snapshot_and_pass.snap", []byte("original output."))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		golden.Verify(&tSpy, "different output.")
+		vfs.AssertContentWasStored(t, fs, "testdata/TestGlobalUpdate/should_rewrite_outdated_snapshot_and_pass.snap", []byte("different output."))
+		helper.AssertPassTest(t, &tSpy)
+	})
+
+	t.Run("should update golden master snapshot", func(t *testing.T) {
+		setUp(t)
+
+		err := fs.WriteFile("testdata/TestGlobalUpdate/should_update_golden_master_snapshot.snap.json", []byte("[]"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		f := func(args ...any) any {
+			return args[0].(int) * 2
+		}
+
+		golden.Master(&tSpy, f, golden.Combine([]any{1, 2}))
+		vfs.AssertSnapShotContains(t, fs, "testdata/TestGlobalUpdate/should_update_golden_master_snapshot.snap.json", `"Params": "2"`)
+		helper.AssertPassTest(t, &tSpy)
+	})
+}
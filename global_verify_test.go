@@ -23,6 +23,7 @@ func TestGlobalVerify(t *testing.T) {
 	var tSpy helper.TSpy
 
 	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
 		// Passing t in each setup guarantees that we are using the right name for the
 		// snapshot, otherwise the name won't be accurate
 
@@ -39,6 +40,13 @@ func TestGlobalVerify(t *testing.T) {
 		}
 	}
 
+	// nextRun simulates running the test again: a new Golden instance that keeps
+	// the snapshots written in previous runs
+
+	nextRun := func() {
+		golden.G = golden.NewUsingFs(fs)
+	}
+
 	t.Run("should create snapshot if not exists", func(t *testing.T) {
 		setUp(t)
 
@@ -58,6 +66,7 @@ func TestGlobalVerify(t *testing.T) {
 		setUp(t)
 
 		golden.Verify(&tSpy, "some output.")
+		nextRun()
 		golden.Verify(&tSpy, "different output.")
 
 		want := []byte(("some output."))
@@ -71,6 +80,7 @@ func TestGlobalVerify(t *testing.T) {
 		// Sets the snapshot for first time
 		golden.Verify(&tSpy, "original output.")
 		// Changes happened. Verify against existing snapshot
+		nextRun()
 		golden.Verify(&tSpy, "different output.")
 
 		helper.AssertFailedTest(t, &tSpy)
@@ -1,24 +1,37 @@
 package golden
 
 import (
+	"fmt"
 	"github.com/franiglesias/golden/internal/combinatory"
 	"github.com/franiglesias/golden/internal/vfs"
-	"log"
-	"sync"
+	"os"
 )
 
 const approvalHeader = "**Approval mode**: Remove WaitApproval() when you are happy with this snapshot.\n%s"
 const verifyHeader = "**Verify mode**\n%s"
+const errorHeader = "**Golden error**: snapshot %s, subject of type %T\n%s"
+const strictApproval = "**Strict mode**: snapshot %s can't be approved. Remove WaitApproval() to verify it."
+const strictUpdate = "**Strict mode**: snapshot %s can't be updated. Disable update mode to verify it."
+const strictMissing = "**Strict mode**: snapshot %s doesn't exist and won't be created.\n%s"
+const updateCreated = "**Update mode**: snapshot %s created."
+const updateUpdated = "**Update mode**: snapshot %s updated.\n%s"
+const updateUnchanged = "**Update mode**: snapshot %s unchanged."
 
 /*
-Golden is the type that manages snapshotting and test evaluation
+Golden is the type that manages snapshotting and test evaluation. It is safe
+for concurrent use. It doesn't embed sync.RWMutex anymore, so it has no Lock or
+Unlock methods: snapshots are locked one by one.
 */
 type Golden struct {
-	sync.RWMutex
 	fs         vfs.Vfs
 	normalizer Normalizer
 	reporter   DiffReporter
 	global     Config
+	usage      *usage
+	sequence   *sequence
+	inline     *inlineEdits
+	locks      *snapshotLocks
+	outcomes   *outcomes
 }
 
 /*
@@ -26,10 +39,35 @@ Verify takes the subject and tries to compare it with the content of the snapsho
 file. If this file doesn't exist, it creates it.
 
 If the contents of the snapshot and the subject are different, the test fails
-and a report with the differences is showed.
+and a report with the differences is showed. The subject is written next to the
+snapshot (TestSomething.received.snap), so you can inspect it with other tools.
+This file is removed when the verification passes.
+
+Verify can be used in parallel tests. Only the tests verifying the same
+snapshot will wait for each other.
+
+If the subject can't be normalized or the snapshot can't be read or written,
+the test is stopped with Fatalf, so only the current test fails.
+
+Successive calls in the same test without a custom snapshot name get their own
+snapshot: TestSomething.snap, TestSomething_2.snap, TestSomething_3.snap...
+
+When the run is in update mode (GOLDEN_UPDATE=1 or -golden.update), the
+snapshot is rewritten with the subject and the test passes.
+
+In read-only mode (ReadOnly option, GOLDEN_READONLY=1 or -golden.readonly) any
+attempt to write the snapshot stops the test, describing what would have been
+written.
+
+Tests can be put in approval mode without adding WaitApproval to the code, with
+a pattern matching their names, like the one of -run, in GOLDEN_APPROVE or
+-golden.approve.
+
+In strict mode (Strict option, StrictInCI option with CI=true, GOLDEN_STRICT=1
+or -golden.strict) a missing snapshot makes the test fail instead of being
+created, and approval and update modes are refused.
 */
 func (g *Golden) Verify(t Failable, s any, options ...Option) {
-	g.Lock()
 	t.Helper()
 
 	conf := g.global
@@ -37,40 +75,157 @@ func (g *Golden) Verify(t Failable, s any, options ...Option) {
 		option(&conf)
 	}
 
-	subject := g.normalize(s, conf.scrubbers)
+	conf.format = g.format()
+	if conf.name == "" {
+		source, err := callerLocation()
+		if err != nil && conf.needsSource() {
+			fatalf(t, errorHeader, t.Name(), s, fmt.Errorf("could not name the snapshot: %w", err))
+			return
+		}
+		conf.source = source
+		conf.index = g.sequence.next(t, conf.snapshotPath(t))
+	}
 
 	name := conf.snapshotPath(t)
+	g.usage.record(t, name, conf)
+	g.usage.watch(t)
+
+	launch := diffTools.hold(t)
+	defer launch()
+	unlock := g.locks.lock(name)
+	defer unlock()
 
-	if conf.approvalMode() {
-		g.approvalFlow(t, name, subject, conf)
-	} else {
-		g.verifyFlow(t, name, subject, conf)
+	subject, err := g.normalize(s, conf.scrubbers)
+	if err != nil {
+		fatalf(t, errorHeader, name, s, err)
+		return
 	}
 
-	g.Unlock()
+	requested, err := approvalRequested(t.Name())
+	if err != nil {
+		fatalf(t, errorHeader, name, s, err)
+		return
+	}
+	conf.approve = conf.approve || requested
+
+	if refusal := conf.strictRefusal(); refusal != "" {
+		fatalf(t, refusal, name)
+		return
+	}
+
+	session := g.session(conf)
+
+	switch {
+	case conf.approvalMode():
+		err = session.approvalFlow(t, name, subject, conf)
+	case updateRequested():
+		err = session.updateFlow(t, name, subject, conf)
+	default:
+		err = session.verifyFlow(t, name, subject, conf)
+	}
+
+	if err != nil {
+		fatalf(t, errorHeader, name, s, err)
+	}
 }
 
-func (g *Golden) approvalFlow(t Failable, name string, subject string, conf Config) {
+func (g *Golden) approvalFlow(t Failable, name string, subject string, conf Config) error {
 	var previous string
-	if g.snapshotExists(name) {
-		previous = g.readSnapshot(name)
+	exists, err := g.snapshotExists(name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		previous, err = g.readSnapshot(name)
+		if err != nil {
+			return err
+		}
 	}
 
-	g.writeSnapshot(name, subject)
+	err = g.writeSnapshot(name, subject)
+	if err != nil {
+		return err
+	}
 
-	t.Errorf(approvalHeader, conf.reporter.Differences(previous, subject))
+	t.Errorf(approvalHeader, conf.differences(t, modeApproval, name, previous, subject))
+	g.outcomes.record(newOutcome(t, name, name, modeApproval, false, previous, subject))
+	return g.discardReceived(name, conf)
 }
 
-func (g *Golden) verifyFlow(t Failable, name string, subject string, conf Config) {
-	if !g.snapshotExists(name) {
-		g.writeSnapshot(name, subject)
+func (g *Golden) verifyFlow(t Failable, name string, subject string, conf Config) error {
+	exists, err := g.snapshotExists(name)
+	if err != nil {
+		return err
+	}
+	if !exists && conf.strictMode() {
+		t.Errorf(strictMissing, name, conf.differences(t, modeStrict, name, "", subject))
+		g.outcomes.record(newOutcome(t, name, name, modeStrict, false, "", subject))
+		return nil
+	}
+	if !exists {
+		err = g.writeSnapshot(name, subject)
+		if err != nil {
+			return err
+		}
 	}
 
-	snapshot := g.readSnapshot(name)
+	snapshot, err := g.readSnapshot(name)
+	if err != nil {
+		return err
+	}
 
 	if snapshot != subject {
-		t.Errorf(verifyHeader, conf.reporter.Differences(snapshot, subject))
+		t.Errorf(verifyHeader, conf.differences(t, modeVerify, name, snapshot, subject))
+		g.outcomes.record(newOutcome(t, name, name, modeVerify, false, snapshot, subject))
+		return g.keepReceived(t, name, subject, conf)
+	}
+	mode := modeVerify
+	if !exists {
+		mode = modeCreated
+	}
+	g.outcomes.record(newOutcome(t, name, name, mode, true, snapshot, subject))
+	return g.discardReceived(name, conf)
+}
+
+/*
+updateFlow rewrites the snapshot with the current subject and the test passes.
+It is activated for the whole run with GOLDEN_UPDATE=1 or -golden.update, and
+logs whether the snapshot was created, updated or left unchanged. Main prints
+a summary, because the logs are only shown with go test -v.
+*/
+func (g *Golden) updateFlow(t Failable, name string, subject string, conf Config) error {
+	exists, err := g.snapshotExists(name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		err = g.writeSnapshot(name, subject)
+		if err != nil {
+			return err
+		}
+		logf(t, updateCreated, name)
+		g.outcomes.record(newOutcome(t, name, name, modeCreated, true, "", subject))
+		return g.discardReceived(name, conf)
+	}
+
+	snapshot, err := g.readSnapshot(name)
+	if err != nil {
+		return err
 	}
+
+	if snapshot == subject {
+		logf(t, updateUnchanged, name)
+		g.outcomes.record(newOutcome(t, name, name, modeUpdate, true, snapshot, subject))
+		return g.discardReceived(name, conf)
+	}
+
+	err = g.writeSnapshot(name, subject)
+	if err != nil {
+		return err
+	}
+	logf(t, updateUpdated, name, conf.differences(t, modeUpdate, name, snapshot, subject))
+	g.outcomes.record(newOutcome(t, name, name, modeUpdate, true, snapshot, subject))
+	return g.discardReceived(name, conf)
 }
 
 /*
@@ -90,43 +245,68 @@ the possible values for each parameter that you would pass to the SUT. This will
 create a lot of tests (tenths or hundredths).
 */
 func (g *Golden) Master(t Failable, f combinatory.Wrapper, values [][]any, options ...Option) {
-	g.global.ext = ".snap.json"
+	t.Helper()
 	subject := combinatory.Master(f, values...)
+	options = append([]Option{Extension(".snap.json")}, options...)
 	g.Verify(t, subject, options...)
 }
 
-func (g *Golden) normalize(s any, scrubbers []Scrubber) string {
+/*
+session returns the Golden instance to use with this configuration. In
+read-only mode, it is a copy that can't write in the filesystem.
+*/
+func (g *Golden) session(conf Config) *Golden {
+	if !conf.readOnlyMode() {
+		return g
+	}
+	readOnly := *g
+	readOnly.fs = vfs.NewReadOnlyFs(g.fs)
+	return &readOnly
+}
+
+/*
+format returns the format of the normalizer, if it declares one
+*/
+func (g *Golden) format() string {
+	if f, ok := g.normalizer.(interface{ Format() string }); ok {
+		return f.Format()
+	}
+	return ""
+}
+
+func (g *Golden) normalize(s any, scrubbers []Scrubber) (string, error) {
 	n, err := g.normalizer.Normalize(s)
 	if err != nil {
-		log.Fatalf("could not normalize subject %s: %s", n, err)
+		return "", fmt.Errorf("could not normalize subject: %w", err)
 	}
 	for _, scrubber := range scrubbers {
 		n = scrubber.Clean(n)
 	}
-	return n
+	return n, nil
 }
 
-func (g *Golden) snapshotExists(name string) bool {
+func (g *Golden) snapshotExists(name string) (bool, error) {
 	snapshotExists, err := g.fs.Exists(name)
 	if err != nil {
-		log.Fatalf("could not determine if snahpshot %s exists: %s", name, err)
+		return false, fmt.Errorf("could not determine if snapshot exists: %w", err)
 	}
-	return snapshotExists
+	return snapshotExists, nil
 }
 
-func (g *Golden) writeSnapshot(name string, n string) {
+func (g *Golden) writeSnapshot(name string, n string) error {
 	err := g.fs.WriteFile(name, []byte(n))
 	if err != nil {
-		log.Fatalf("could not create snapshot %s: %s", name, err)
+		return fmt.Errorf("could not create snapshot: %w", err)
 	}
+	return nil
 }
 
-func (g *Golden) readSnapshot(name string) string {
+func (g *Golden) readSnapshot(name string) (string, error) {
 	snapshot, err := g.fs.ReadFile(name)
 	if err != nil {
-		log.Fatalf("could not read snapshot %s: %s", name, err)
+		return "", fmt.Errorf("could not read snapshot: %w", err)
 	}
-	return string(snapshot)
+	return string(snapshot), nil
 }
 
 func (g *Golden) Defaults(opts ...Option) {
@@ -159,6 +339,17 @@ func Verify(t Failable, subject any, options ...Option) {
 	G.Verify(t, subject, options...)
 }
 
+/*
+VerifyInline see Golden.VerifyInline
+
+TL;DR Verify the subject against a snapshot written in the test source
+
+This is a tiny wrapper around the Golden.VerifyInline method.
+*/
+func VerifyInline(t Failable, subject any, snapshot InlineSnapshot, options ...Option) {
+	G.VerifyInline(t, subject, snapshot, options...)
+}
+
 /*
 Master see Golden.Master
 
@@ -174,6 +365,21 @@ func Defaults(options ...Option) {
 	G.Defaults(options...)
 }
 
+/*
+Main see Golden.Main
+
+TL;DR Run the tests and report obsolete snapshots
+
+Use it in the TestMain function of your package:
+
+	func TestMain(m *testing.M) {
+		golden.Main(m)
+	}
+*/
+func Main(m Runner) {
+	os.Exit(G.Main(m))
+}
+
 /*
 New initializes a new Golden object with defaults. Usually you don't need to
 invoke it directly, because it is used to initialize the G var. You may invoke
@@ -196,9 +402,15 @@ func NewUsingFs(fs vfs.Vfs) *Golden {
 			ext:      ".snap",
 			approve:  false,
 			reporter: LineDiffReporter{},
+			layout:   NestedLayout{},
 		},
 		fs:         fs,
 		normalizer: JsonNormalizer{},
+		usage:      newUsage(fs),
+		sequence:   newSequence(),
+		inline:     newInlineEdits(),
+		locks:      newSnapshotLocks(),
+		outcomes:   newOutcomes(),
 	}
 }
 
@@ -210,6 +422,10 @@ Interfaces
 
 /*
 Failable interface allows us to replace *testing.T in the own library tests.
+
+Golden also uses Fatalf and Logf when the Failable provides them, like
+*testing.T does. Without Fatalf, errors are reported with Errorf, and without
+Logf, update mode messages are not shown.
 */
 type Failable interface {
 	Errorf(format string, args ...any)
@@ -217,6 +433,38 @@ type Failable interface {
 	Name() string
 }
 
+type fatalFailable interface {
+	Fatalf(format string, args ...any)
+}
+
+type logFailable interface {
+	Logf(format string, args ...any)
+}
+
+/*
+fatalf stops the test if t can do it. Callers must return right after it,
+because other Failable implementations only get the error.
+*/
+func fatalf(t Failable, format string, args ...any) {
+	t.Helper()
+	if f, ok := t.(fatalFailable); ok {
+		f.Fatalf(format, args...)
+		return
+	}
+	t.Errorf(format, args...)
+}
+
+/*
+logf logs the message if t can do it, like *testing.T does. Other Failable
+implementations don't show the messages of update mode.
+*/
+func logf(t Failable, format string, args ...any) {
+	t.Helper()
+	if l, ok := t.(logFailable); ok {
+		l.Logf(format, args...)
+	}
+}
+
 /*
 Normalizer normalizes the subject to a string representation that can be compared
 */
@@ -231,3 +479,53 @@ between expected snapshot and subject
 type DiffReporter interface {
 	Differences(want, got string) string
 }
+
+/*
+ReportContext describes the snapshot whose differences are being reported
+*/
+type ReportContext struct {
+	// Path of the snapshot, or location of the VerifyInline call
+	Path string
+	// TestName is the full name of the test, as returned by t.Name()
+	TestName string
+	// Mode is verify, approval, update or strict (missing snapshot in strict mode)
+	Mode string
+	// Format of the normalized subject, like json, if the normalizer declares it
+	Format string
+	// Scrubbers applied to the subject
+	Scrubbers []Scrubber
+}
+
+/*
+ContextDiffReporter is a DiffReporter that needs to know about the
+verification, for example to show the path of the snapshot or the test that
+verified it. Golden uses ContextDifferences when the reporter implements it.
+*/
+type ContextDiffReporter interface {
+	DiffReporter
+	ContextDifferences(ctx ReportContext, want, got string) string
+}
+
+/*
+AdaptReporter turns a DiffReporter into a ContextDiffReporter that ignores the
+context, so reporters written for the old interface can be used where a
+ContextDiffReporter is expected
+*/
+func AdaptReporter(reporter DiffReporter) ContextDiffReporter {
+	if r, ok := reporter.(ContextDiffReporter); ok {
+		return r
+	}
+	return reporterAdapter{reporter: reporter}
+}
+
+type reporterAdapter struct {
+	reporter DiffReporter
+}
+
+func (a reporterAdapter) Differences(want, got string) string {
+	return a.reporter.Differences(want, got)
+}
+
+func (a reporterAdapter) ContextDifferences(_ ReportContext, want, got string) string {
+	return a.reporter.Differences(want, got)
+}
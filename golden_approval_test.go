@@ -16,6 +16,7 @@ func TestApproval(t *testing.T) {
 	var tSpy helper.TSpy
 
 	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
 		fs = vfs.NewMemFs()
 		gld = *golden.NewUsingFs(fs)
 		tSpy = helper.TSpy{
@@ -23,6 +24,13 @@ func TestApproval(t *testing.T) {
 		}
 	}
 
+	// nextRun simulates running the test again: a new Golden instance that keeps
+	// the snapshots written in previous runs
+
+	nextRun := func() {
+		gld = *golden.NewUsingFs(fs)
+	}
+
 	t.Run("should create snapshot and fail", func(t *testing.T) {
 		setUp(t)
 
@@ -44,6 +52,7 @@ func TestApproval(t *testing.T) {
 		vfs.AssertContentWasStored(t, fs, "testdata/TestApproval/should_keep_test_failing_while_approval_mode.snap", []byte("starting subject."))
 		tSpy.Reset()
 
+		nextRun()
 		gld.Verify(&tSpy, "updated subject.", golden.WaitApproval())
 		helper.AssertFailedTest(t, &tSpy)
 		vfs.AssertContentWasStored(t, fs, "testdata/TestApproval/should_keep_test_failing_while_approval_mode.snap", []byte("updated subject."))
@@ -62,12 +71,14 @@ func TestApproval(t *testing.T) {
 
 		// After this run the snapshot will be approved by an expert
 
+		nextRun()
 		gld.Verify(&tSpy, "updated subject.", golden.WaitApproval())
 		tSpy.Reset()
 
 		// At this point, the snapshot was approved, so we can change the test back to
 		// Verification mode, removing the golden.WaitApproval() option
 
+		nextRun()
 		gld.Verify(&tSpy, "updated subject.")
 		helper.AssertPassTest(t, &tSpy)
 	})
@@ -107,6 +118,7 @@ func TestApproval(t *testing.T) {
 		gld.Verify(&tSpy, "original output.", golden.WaitApproval())
 
 		// Changes happened. Verify against existing snapshot
+		nextRun()
 		gld.Verify(&tSpy, "different output.", golden.WaitApproval())
 
 		helper.AssertFailedTest(t, &tSpy)
@@ -0,0 +1,90 @@
+package golden_test
+
+import (
+	"github.com/franiglesias/golden"
+	"github.com/franiglesias/golden/internal/helper"
+	"github.com/franiglesias/golden/internal/vfs"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+/*
+TestApprovePattern needs the same setup as TestVerify. Check it for
+documentation.
+*/
+func TestApprovePattern(t *testing.T) {
+	var gld golden.Golden
+	var fs *vfs.MemFs
+	var tSpy helper.TSpy
+
+	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
+		fs = vfs.NewMemFs()
+		gld = *golden.NewUsingFs(fs)
+		tSpy = helper.TSpy{
+			T: t,
+		}
+	}
+
+	t.Run("should approve matching test", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_APPROVE", "TestApprove.*/should_approve_match")
+		err := fs.WriteFile("testdata/TestApprovePattern/should_approve_matching_test.snap", []byte("original subject."))
+		assert.NoError(t, err)
+
+		gld.Verify(&tSpy, "new subject.")
+		helper.AssertFailedTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "**Approval mode**")
+		vfs.AssertContentWasStored(t, fs, "testdata/TestApprovePattern/should_approve_matching_test.snap", []byte("new subject."))
+	})
+
+	t.Run("should verify not matching test", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_APPROVE", "TestApprovePattern/should_approve")
+		err := fs.WriteFile("testdata/TestApprovePattern/should_verify_not_matching_test.snap", []byte("original subject."))
+		assert.NoError(t, err)
+
+		gld.Verify(&tSpy, "new subject.")
+		helper.AssertFailedTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "**Verify mode**")
+		vfs.AssertContentWasStored(t, fs, "testdata/TestApprovePattern/should_verify_not_matching_test.snap", []byte("original subject."))
+	})
+
+	t.Run("should approve subtests of matching test", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_APPROVE", "TestApprovePattern")
+
+		t.Run("subtest", func(t *testing.T) {
+			tSpy.T = t
+			gld.Verify(&tSpy, "new subject.")
+			helper.AssertFailedTest(t, &tSpy)
+			helper.AssertReportContains(t, &tSpy, "**Approval mode**")
+		})
+	})
+
+	t.Run("should approve in golden master", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_APPROVE", "/golden_master$")
+
+		gld.Master(&tSpy, func(args ...any) any { return args[0] }, golden.Combine([]any{1}))
+		helper.AssertFailedTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "**Approval mode**")
+	})
+
+	t.Run("should fail with invalid pattern", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_APPROVE", "Test[")
+
+		gld.Verify(&tSpy, "new subject.")
+		helper.AssertFatalTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "invalid approval pattern Test[")
+	})
+
+	t.Run("should be refused in strict mode", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_APPROVE", "TestApprovePattern")
+
+		gld.Verify(&tSpy, "new subject.", golden.Strict())
+		helper.AssertFatalTest(t, &tSpy)
+	})
+}
@@ -2,6 +2,7 @@ package golden_test
 
 import (
 	"github.com/franiglesias/golden"
+	"github.com/franiglesias/golden/internal/helper"
 	"github.com/franiglesias/golden/internal/vfs"
 	"testing"
 )
@@ -16,6 +17,7 @@ func TestDefaults(t *testing.T) {
 	var fs *vfs.MemFs
 
 	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
 		// Passing t in each setup guarantees that we are using the right name for the
 		// snapshot, otherwise the name won't be accurate
 
@@ -50,4 +52,11 @@ func TestDefaults(t *testing.T) {
 		gld.Verify(t, "example subject.")
 		vfs.AssertSnapshotWasCreated(t, fs, "testdata/TestDefaults/should_not_allow_set_default_snapshot_name.snap")
 	})
+
+	t.Run("should use defaults defined layout in all tests", func(t *testing.T) {
+		setUp(t)
+		gld.Defaults(golden.Layout(golden.FlatLayout{}))
+		gld.Verify(t, "example subject.")
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/TestDefaults__should_use_defaults_defined_layout_in_all_tests.snap")
+	})
 }
@@ -0,0 +1,131 @@
+package golden_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/franiglesias/golden"
+	"github.com/franiglesias/golden/internal/helper"
+	"github.com/franiglesias/golden/internal/vfs"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+/*
+TestErrors checks that problems managing the snapshot are reported through the
+test, so only the offending test fails. It uses a filesystem that can be
+configured to fail.
+*/
+func TestErrors(t *testing.T) {
+	var gld golden.Golden
+	var fs *vfs.FailingFs
+	var tSpy helper.TSpy
+
+	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
+		fs = vfs.NewFailingFs()
+		gld = *golden.NewUsingFs(fs)
+		tSpy = helper.TSpy{
+			T: t,
+		}
+	}
+
+	t.Run("should fail if subject can't be normalized", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, make(chan int))
+		helper.AssertFatalTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "testdata/TestErrors/should_fail_if_subject_can't_be_normalized.snap")
+		helper.AssertReportContains(t, &tSpy, "subject of type chan int")
+		helper.AssertReportContains(t, &tSpy, "could not normalize subject")
+	})
+
+	t.Run("should fail if snapshot existence can't be determined", func(t *testing.T) {
+		setUp(t)
+		fs.ExistsError = errors.New("permission denied")
+
+		gld.Verify(&tSpy, "some subject.")
+		helper.AssertFatalTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "testdata/TestErrors/should_fail_if_snapshot_existence_can't_be_determined.snap")
+		helper.AssertReportContains(t, &tSpy, "subject of type string")
+		helper.AssertReportContains(t, &tSpy, "permission denied")
+	})
+
+	t.Run("should fail if snapshot can't be written", func(t *testing.T) {
+		setUp(t)
+		fs.WriteError = errors.New("disk full")
+
+		gld.Verify(&tSpy, "some subject.")
+		helper.AssertFatalTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "could not create snapshot: disk full")
+	})
+
+	t.Run("should fail if snapshot can't be read", func(t *testing.T) {
+		setUp(t)
+		fs.ReadError = errors.New("i/o error")
+
+		gld.Verify(&tSpy, "some subject.")
+		helper.AssertFatalTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "could not read snapshot: i/o error")
+	})
+
+	t.Run("should fail in approval mode if snapshot can't be written", func(t *testing.T) {
+		setUp(t)
+		fs.WriteError = errors.New("disk full")
+
+		gld.Verify(&tSpy, "some subject.", golden.WaitApproval())
+		helper.AssertFatalTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "could not create snapshot: disk full")
+	})
+
+	t.Run("should fail in update mode if snapshot can't be written", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_UPDATE", "1")
+		fs.WriteError = errors.New("disk full")
+
+		gld.Verify(&tSpy, "some subject.")
+		helper.AssertFatalTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "could not create snapshot: disk full")
+	})
+
+	t.Run("should allow next verifications after a failure", func(t *testing.T) {
+		setUp(t)
+		fs.WriteError = errors.New("disk full")
+
+		gld.Verify(&tSpy, "some subject.")
+		helper.AssertFatalTest(t, &tSpy)
+
+		tSpy.Reset()
+		fs.WriteError = nil
+
+		gld.Verify(&tSpy, "some subject.", golden.Snapshot("another"))
+		helper.AssertPassTest(t, &tSpy)
+	})
+
+	t.Run("should report errors with Errorf if the Failable has no Fatalf", func(t *testing.T) {
+		setUp(t)
+		fs.WriteError = errors.New("disk full")
+		failable := &errorfOnly{name: t.Name()}
+
+		gld.Verify(failable, "some subject.")
+		assert.Contains(t, failable.report, "could not create snapshot: disk full")
+	})
+}
+
+/*
+errorfOnly is a Failable with only the required methods, like the ones written
+before Golden used Fatalf and Logf
+*/
+type errorfOnly struct {
+	name   string
+	report string
+}
+
+func (e *errorfOnly) Errorf(format string, args ...any) {
+	e.report = fmt.Sprintf(format, args...)
+}
+
+func (e *errorfOnly) Helper() {}
+
+func (e *errorfOnly) Name() string {
+	return e.name
+}
@@ -0,0 +1,201 @@
+package golden_test
+
+import (
+	"github.com/franiglesias/golden"
+	"github.com/franiglesias/golden/internal/helper"
+	"github.com/franiglesias/golden/internal/vfs"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+/*
+TestVerifyInline needs the same setup as TestVerify. Check it for documentation.
+
+VerifyInline rewrites the test source, so we copy this very file into the
+in-memory filesystem and inspect the rewritten copy. The real file is never
+touched.
+*/
+func TestVerifyInline(t *testing.T) {
+	var gld golden.Golden
+	var fs *vfs.MemFs
+	var tSpy helper.TSpy
+
+	_, source, _, _ := runtime.Caller(0)
+
+	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
+		fs = vfs.NewMemFs()
+		gld = *golden.NewUsingFs(fs)
+		tSpy = helper.TSpy{
+			T: t,
+		}
+
+		content, err := os.ReadFile(source)
+		assert.NoError(t, err)
+		err = fs.WriteFile(source, content)
+		assert.NoError(t, err)
+	}
+
+	// rewritten returns the test source after VerifyInline did its job
+	rewritten := func(t *testing.T) string {
+		content, err := fs.ReadFile(source)
+		assert.NoError(t, err)
+		return string(content)
+	}
+
+	// inline builds the expected code, so it doesn't appear verbatim in this file
+	inline := func(literal string) string {
+		return "golden." + "Inline(" + literal + ")"
+	}
+
+	t.Run("should write empty inline snapshot and pass", func(t *testing.T) {
+		setUp(t)
+
+		gld.VerifyInline(&tSpy, "created inline.", golden.Inline(""))
+		helper.AssertPassTest(t, &tSpy)
+		assert.Contains(t, rewritten(t), inline("`created inline.`"))
+	})
+
+	t.Run("should pass when subject matches", func(t *testing.T) {
+		setUp(t)
+
+		gld.VerifyInline(&tSpy, "some subject.", golden.Inline(`some subject.`))
+		helper.AssertPassTest(t, &tSpy)
+	})
+
+	t.Run("should ignore surrounding line breaks", func(t *testing.T) {
+		setUp(t)
+
+		gld.VerifyInline(&tSpy, "first line\nsecond line", golden.Inline(`
+first line
+second line
+`))
+		helper.AssertPassTest(t, &tSpy)
+	})
+
+	t.Run("should fail and report differences", func(t *testing.T) {
+		setUp(t)
+
+		gld.VerifyInline(&tSpy, "different output.", golden.Inline(`original output.`))
+		helper.AssertFailedTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "-original output.\n+different output.\n")
+		assert.NotContains(t, rewritten(t), inline("`different output.`"))
+	})
+
+	t.Run("should normalize and scrub subject", func(t *testing.T) {
+		setUp(t)
+
+		scrubber := golden.NewScrubber("\\d{4}-\\d{2}-\\d{2}", "<Date>")
+		subject := map[string]string{"date": "2024-01-31"}
+
+		gld.VerifyInline(&tSpy, subject, golden.Inline(""), golden.WithScrubbers(scrubber))
+		helper.AssertPassTest(t, &tSpy)
+		assert.Contains(t, rewritten(t), "\"date\": \"<Date>\"\n}\n`")
+	})
+
+	t.Run("should rewrite outdated snapshot in approval mode and fail", func(t *testing.T) {
+		setUp(t)
+
+		gld.VerifyInline(&tSpy, "approved output.", golden.Inline(`original output.`), golden.WaitApproval())
+		helper.AssertFailedTest(t, &tSpy)
+		assert.Contains(t, rewritten(t), inline("`approved output.`"))
+	})
+
+	t.Run("should rewrite outdated snapshot in update mode and pass", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_UPDATE", "1")
+
+		gld.VerifyInline(&tSpy, "updated output.", golden.Inline(`original output.`))
+		helper.AssertPassTest(t, &tSpy)
+		helper.AssertLogContains(t, &tSpy, "updated")
+		assert.Contains(t, rewritten(t), inline("`updated output.`"))
+	})
+
+	t.Run("should write multi-line subjects in their own lines", func(t *testing.T) {
+		setUp(t)
+
+		gld.VerifyInline(&tSpy, "line one\nline two", golden.Inline(""))
+		assert.Contains(t, rewritten(t), inline("`\nline one\nline two\n`"))
+	})
+
+	t.Run("should keep leading and trailing line breaks of the subject", func(t *testing.T) {
+		setUp(t)
+		// normalization trims line breaks, but scrubbers can add them
+		breaks := golden.WithScrubbers(golden.NewScrubber("^", "\n"), golden.NewScrubber("$", "\n"))
+
+		gld.VerifyInline(&tSpy, "line one\nline two", golden.Inline(""), breaks)
+		assert.Contains(t, rewritten(t), inline("`\n\nline one\nline two\n\n`"))
+
+		tSpy.Reset()
+		gld.VerifyInline(&tSpy, "line one\nline two", golden.Inline(`
+
+line one
+line two
+
+`), breaks)
+		helper.AssertPassTest(t, &tSpy)
+	})
+
+	t.Run("should quote subjects that can't be raw strings", func(t *testing.T) {
+		setUp(t)
+
+		gld.VerifyInline(&tSpy, "uses `backticks`", golden.Inline(""))
+		assert.Contains(t, rewritten(t), inline(`"uses `+"`backticks`"+`"`))
+	})
+
+	t.Run("should locate calls after previous rewrites in the same file", func(t *testing.T) {
+		setUp(t)
+
+		gld.VerifyInline(&tSpy, "first\nmulti-line\nsubject", golden.Inline(""))
+		gld.VerifyInline(&tSpy, "second subject", golden.Inline(""))
+
+		source := rewritten(t)
+		assert.Contains(t, source, inline("`\nfirst\nmulti-line\nsubject\n`"))
+		assert.Contains(t, source, inline("`second subject`"))
+	})
+
+	t.Run("should fail if a call in a loop is rewritten with other subject", func(t *testing.T) {
+		setUp(t)
+
+		for _, subject := range []string{"first", "second"} {
+			gld.VerifyInline(&tSpy, subject, golden.Inline(""))
+		}
+
+		helper.AssertFatalTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "was already rewritten in this run with another subject")
+		assert.Contains(t, rewritten(t), inline("`first`"))
+	})
+
+	t.Run("should pass if a call in a loop gets the same subject", func(t *testing.T) {
+		setUp(t)
+
+		for i := 0; i < 2; i++ {
+			gld.VerifyInline(&tSpy, "same", golden.Inline(""))
+		}
+
+		helper.AssertPassTest(t, &tSpy)
+		assert.Contains(t, rewritten(t), inline("`same`"))
+	})
+
+	t.Run("should fail if source can't be rewritten", func(t *testing.T) {
+		setUp(t)
+		_ = fs.Remove(source)
+
+		gld.VerifyInline(&tSpy, "some subject.", golden.Inline(""))
+		helper.AssertFatalTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "could not read test source")
+	})
+
+	t.Run("should keep the rest of the source untouched", func(t *testing.T) {
+		setUp(t)
+		original := rewritten(t)
+
+		gld.VerifyInline(&tSpy, "untouched", golden.Inline(""))
+
+		assert.Equal(t, strings.Count(original, "\n"), strings.Count(rewritten(t), "\n"))
+		assert.Contains(t, rewritten(t), "// inline builds the expected code, so it doesn't appear verbatim in this file")
+	})
+}
@@ -18,6 +18,7 @@ func TestGoldenMaster(t *testing.T) {
 	var tSpy helper.TSpy
 
 	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
 		fs = vfs.NewMemFs()
 		gld = *golden.NewUsingFs(fs)
 		tSpy = helper.TSpy{
@@ -0,0 +1,176 @@
+package golden_test
+
+import (
+	"github.com/franiglesias/golden"
+	"github.com/franiglesias/golden/internal/helper"
+	"github.com/franiglesias/golden/internal/vfs"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+/*
+TestObsolete needs the same setup as TestVerify. Check it for documentation.
+*/
+func TestObsolete(t *testing.T) {
+	var gld golden.Golden
+	var fs *vfs.MemFs
+	var tSpy helper.TSpy
+
+	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
+		fs = vfs.NewMemFs()
+		gld = *golden.NewUsingFs(fs)
+		tSpy = helper.TSpy{
+			T: t,
+		}
+	}
+
+	// writeFile simulates snapshots and other files that exist before the run
+	writeFile := func(t *testing.T, name string) {
+		err := fs.WriteFile(name, []byte("content"))
+		assert.NoError(t, err)
+	}
+
+	t.Run("should report snapshots not used in the run", func(t *testing.T) {
+		setUp(t)
+		writeFile(t, "testdata/TestRenamed.snap")
+		writeFile(t, "testdata/TestDeleted/some_case.snap")
+
+		gld.Verify(&tSpy, "some subject.")
+
+		obsolete, err := gld.Obsolete()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"testdata/TestDeleted/some_case.snap", "testdata/TestRenamed.snap"}, obsolete)
+	})
+
+	t.Run("should not report used snapshots", func(t *testing.T) {
+		setUp(t)
+		writeFile(t, "testdata/TestObsolete/should_not_report_used_snapshots.snap")
+		writeFile(t, "testdata/custom.snap")
+
+		gld.Verify(&tSpy, "content")
+		gld.Verify(&tSpy, "content", golden.Snapshot("custom"))
+
+		obsolete, err := gld.Obsolete()
+		assert.NoError(t, err)
+		assert.Empty(t, obsolete)
+	})
+
+	t.Run("should ignore files that are not snapshots", func(t *testing.T) {
+		setUp(t)
+		writeFile(t, "testdata/fixture.csv")
+
+		gld.Verify(&tSpy, "some subject.")
+
+		obsolete, err := gld.Obsolete()
+		assert.NoError(t, err)
+		assert.Empty(t, obsolete)
+	})
+
+	t.Run("should tell received files from snapshots with the mark in their names", func(t *testing.T) {
+		setUp(t)
+		writeFile(t, "testdata/TestOld.received.snap")
+		writeFile(t, "testdata/Test.receivedOrders.snap")
+
+		gld.Verify(&tSpy, "some subject.")
+
+		obsolete, err := gld.Obsolete()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"testdata/Test.receivedOrders.snap"}, obsolete)
+	})
+
+	t.Run("should inspect every folder and extension used", func(t *testing.T) {
+		setUp(t)
+		writeFile(t, "__snapshots/old.snap")
+		writeFile(t, "testdata/old_master.snap.json")
+
+		gld.Verify(&tSpy, "some subject.", golden.Folder("__snapshots"))
+		gld.Master(&tSpy, func(args ...any) any { return args[0] }, golden.Combine([]any{1}))
+
+		obsolete, err := gld.Obsolete()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"__snapshots/old.snap", "testdata/old_master.snap.json"}, obsolete)
+	})
+
+	t.Run("should inspect files matching the path templates used", func(t *testing.T) {
+		setUp(t)
+		writeFile(t, "testdata/TestDeleted/some_case.golden.json")
+		writeFile(t, "testdata/TestRenamed.golden.json")
+		writeFile(t, "testdata/fixture.json")
+		writeFile(t, "testdata/other.snap")
+
+		gld.Verify(&tSpy, "some subject.", golden.PathTemplate("{test}/{subtest}.golden.{format}"))
+
+		obsolete, err := gld.Obsolete()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"testdata/TestDeleted/some_case.golden.json", "testdata/TestRenamed.golden.json"}, obsolete)
+	})
+
+	t.Run("should only report snapshots of its own tests in folders outside the package", func(t *testing.T) {
+		setUp(t)
+		writeFile(t, "../shared/TestOtherPackage.snap")
+		writeFile(t, "../shared/TestObsolete/deleted_case.snap")
+
+		gld.Verify(&tSpy, "some subject.", golden.Folder("../shared"))
+
+		obsolete, err := gld.Obsolete()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"../shared/TestObsolete/deleted_case.snap"}, obsolete)
+	})
+
+	t.Run("should refuse to prune folders outside the package", func(t *testing.T) {
+		setUp(t)
+		writeFile(t, "../shared/TestObsolete/deleted_case.snap")
+
+		gld.Verify(&tSpy, "some subject.", golden.Folder("../shared"))
+
+		_, err := gld.Prune()
+		assert.ErrorContains(t, err, "snapshots folder ../shared is outside the package")
+		exists, _ := fs.Exists("../shared/TestObsolete/deleted_case.snap")
+		assert.True(t, exists)
+	})
+
+	t.Run("should refuse to prune if other instance verified in the same filesystem", func(t *testing.T) {
+		setUp(t)
+		writeFile(t, "testdata/TestRenamed.snap")
+		other := golden.NewUsingFs(fs)
+
+		gld.Verify(&tSpy, "some subject.")
+		other.Verify(&tSpy, "some subject.", golden.Snapshot("other"))
+
+		_, err := gld.Prune()
+		assert.ErrorContains(t, err, "other Golden instances verified snapshots in the same filesystem")
+		exists, _ := fs.Exists("testdata/other.snap")
+		assert.True(t, exists)
+	})
+
+	t.Run("should refuse to prune if a test was skipped", func(t *testing.T) {
+		setUp(t)
+		writeFile(t, "testdata/TestRenamed.snap")
+
+		t.Run("skipped", func(t *testing.T) {
+			gld.Verify(&helper.TSpy{T: t}, "some subject.")
+			t.Skip("skipped after verifying")
+		})
+
+		_, err := gld.Prune()
+		assert.ErrorContains(t, err, "some tests were skipped")
+		exists, _ := fs.Exists("testdata/TestRenamed.snap")
+		assert.True(t, exists)
+	})
+
+	t.Run("should remove obsolete snapshots when pruning", func(t *testing.T) {
+		setUp(t)
+		writeFile(t, "testdata/TestRenamed.snap")
+
+		gld.Verify(&tSpy, "some subject.")
+
+		removed, err := gld.Prune()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"testdata/TestRenamed.snap"}, removed)
+
+		exists, _ := fs.Exists("testdata/TestRenamed.snap")
+		assert.False(t, exists)
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/TestObsolete/should_remove_obsolete_snapshots_when_pruning.snap")
+	})
+}
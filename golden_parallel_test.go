@@ -0,0 +1,131 @@
+package golden_test
+
+import (
+	"fmt"
+	"github.com/franiglesias/golden"
+	"github.com/franiglesias/golden/internal/helper"
+	"github.com/franiglesias/golden/internal/vfs"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/*
+slowFs simulates a filesystem with latency and counts how many reads are
+happening at the same time
+*/
+type slowFs struct {
+	*vfs.MemFs
+	delay   time.Duration
+	current int32
+	max     int32
+}
+
+func (fs *slowFs) ReadFile(name string) ([]byte, error) {
+	current := atomic.AddInt32(&fs.current, 1)
+	defer atomic.AddInt32(&fs.current, -1)
+	for {
+		max := atomic.LoadInt32(&fs.max)
+		if current <= max || atomic.CompareAndSwapInt32(&fs.max, max, current) {
+			break
+		}
+	}
+	time.Sleep(fs.delay)
+	return fs.MemFs.ReadFile(name)
+}
+
+/*
+TestParallel verifies that Golden can be shared by parallel tests. Run it with
+the race detector:
+
+	go test -race -run TestParallel
+*/
+func TestParallel(t *testing.T) {
+	helper.ClearModes(t)
+	const subtests = 20
+
+	t.Run("should verify independent snapshots", func(t *testing.T) {
+		fs := vfs.NewMemFs()
+		gld := golden.NewUsingFs(fs)
+
+		t.Run("group", func(t *testing.T) {
+			for i := 0; i < subtests; i++ {
+				i := i
+				t.Run(fmt.Sprintf("case_%d", i), func(t *testing.T) {
+					t.Parallel()
+					gld.Verify(t, fmt.Sprintf("subject %d", i))
+					gld.Verify(t, fmt.Sprintf("next step %d", i))
+					gld.Master(t, func(args ...any) any { return args[0] }, golden.Combine([]any{i}))
+				})
+			}
+		})
+
+		for i := 0; i < subtests; i++ {
+			name := fmt.Sprintf("testdata/TestParallel/should_verify_independent_snapshots/group/case_%d", i)
+			vfs.AssertContentWasStored(t, fs, name+".snap", []byte(fmt.Sprintf("subject %d", i)))
+			vfs.AssertContentWasStored(t, fs, name+"_2.snap", []byte(fmt.Sprintf("next step %d", i)))
+			vfs.AssertSnapshotWasCreated(t, fs, name+".snap.json")
+		}
+	})
+
+	t.Run("should protect snapshots shared by several tests", func(t *testing.T) {
+		fs := vfs.NewMemFs()
+		gld := golden.NewUsingFs(fs)
+
+		t.Run("group", func(t *testing.T) {
+			for i := 0; i < subtests; i++ {
+				t.Run(fmt.Sprintf("case_%d", i), func(t *testing.T) {
+					t.Parallel()
+					gld.Verify(t, "shared subject", golden.Snapshot("shared"))
+				})
+			}
+		})
+
+		vfs.AssertContentWasStored(t, fs, "testdata/shared.snap", []byte("shared subject"))
+	})
+
+	// The following tests use goroutines instead of t.Parallel, because the
+	// number of parallel tests is limited by GOMAXPROCS. Each goroutine gets its
+	// own TSpy, so a failure is never reported out of the test goroutine.
+
+	verifyConcurrently := func(t *testing.T, gld *golden.Golden, snapshot func(i int) string) {
+		spies := make([]helper.TSpy, subtests)
+		var wg sync.WaitGroup
+		for i := 0; i < subtests; i++ {
+			spies[i] = helper.TSpy{T: t}
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				gld.Verify(&spies[i], "subject", golden.Snapshot(snapshot(i)))
+			}(i)
+		}
+		wg.Wait()
+		for i := range spies {
+			helper.AssertPassTest(t, &spies[i])
+		}
+	}
+
+	t.Run("should verify independent snapshots concurrently", func(t *testing.T) {
+		fs := &slowFs{MemFs: vfs.NewMemFs(), delay: 20 * time.Millisecond}
+		gld := golden.NewUsingFs(fs)
+
+		verifyConcurrently(t, gld, func(i int) string {
+			return fmt.Sprintf("concurrent_%d", i)
+		})
+
+		assert.Greater(t, atomic.LoadInt32(&fs.max), int32(1), "no concurrent reads happened")
+	})
+
+	t.Run("should serialize verifications of the same snapshot", func(t *testing.T) {
+		fs := &slowFs{MemFs: vfs.NewMemFs(), delay: time.Millisecond}
+		gld := golden.NewUsingFs(fs)
+
+		verifyConcurrently(t, gld, func(i int) string {
+			return "shared"
+		})
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&fs.max), "same snapshot was read concurrently")
+	})
+}
@@ -0,0 +1,100 @@
+package golden_test
+
+import (
+	"github.com/franiglesias/golden"
+	"github.com/franiglesias/golden/internal/helper"
+	"github.com/franiglesias/golden/internal/vfs"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"runtime"
+	"testing"
+)
+
+/*
+TestReadOnly needs the same setup as TestVerify. Check it for documentation.
+*/
+func TestReadOnly(t *testing.T) {
+	var gld golden.Golden
+	var fs *vfs.MemFs
+	var tSpy helper.TSpy
+
+	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
+		fs = vfs.NewMemFs()
+		gld = *golden.NewUsingFs(fs)
+		tSpy = helper.TSpy{
+			T: t,
+		}
+	}
+
+	assertNoSnapshot := func(t *testing.T, name string) {
+		exists, err := fs.Exists(name)
+		assert.NoError(t, err)
+		assert.Falsef(t, exists, "snapshot %s was created", name)
+	}
+
+	t.Run("should fail instead of creating snapshot", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "some subject.", golden.ReadOnly())
+		helper.AssertFatalTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "read-only mode: would have written 13 bytes to testdata/TestReadOnly/should_fail_instead_of_creating_snapshot.snap:\nsome subject.")
+		assertNoSnapshot(t, "testdata/TestReadOnly/should_fail_instead_of_creating_snapshot.snap")
+	})
+
+	t.Run("should verify existing snapshot", func(t *testing.T) {
+		setUp(t)
+		err := fs.WriteFile("testdata/TestReadOnly/should_verify_existing_snapshot.snap", []byte("some subject."))
+		assert.NoError(t, err)
+
+		gld.Verify(&tSpy, "some subject.", golden.ReadOnly())
+		helper.AssertPassTest(t, &tSpy)
+	})
+
+	t.Run("should fail instead of writing in approval mode", func(t *testing.T) {
+		setUp(t)
+		err := fs.WriteFile("testdata/TestReadOnly/should_fail_instead_of_writing_in_approval_mode.snap", []byte("original subject."))
+		assert.NoError(t, err)
+
+		gld.Verify(&tSpy, "new subject.", golden.ReadOnly(), golden.WaitApproval())
+		helper.AssertFatalTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "would have written")
+		vfs.AssertContentWasStored(t, fs, "testdata/TestReadOnly/should_fail_instead_of_writing_in_approval_mode.snap", []byte("original subject."))
+	})
+
+	t.Run("should be activated by environment", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_READONLY", "1")
+
+		gld.Verify(&tSpy, "some subject.")
+		helper.AssertFatalTest(t, &tSpy)
+		assertNoSnapshot(t, "testdata/TestReadOnly/should_be_activated_by_environment.snap")
+	})
+
+	t.Run("should not rewrite inline snapshots", func(t *testing.T) {
+		setUp(t)
+		_, source, _, _ := runtime.Caller(0)
+		content, err := os.ReadFile(source)
+		assert.NoError(t, err)
+		err = fs.WriteFile(source, content)
+		assert.NoError(t, err)
+
+		gld.VerifyInline(&tSpy, "some subject.", golden.Inline(""), golden.ReadOnly())
+		helper.AssertFatalTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "would have written")
+		vfs.AssertContentWasStored(t, fs, source, content)
+	})
+
+	t.Run("should not prune obsolete snapshots", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_READONLY", "1")
+		err := fs.WriteFile("testdata/TestOld.snap", []byte("old"))
+		assert.NoError(t, err)
+
+		gld.Verify(&tSpy, "some subject.", golden.Snapshot("TestOld_renamed"))
+
+		_, err = gld.Prune()
+		assert.Error(t, err)
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/TestOld.snap")
+	})
+}
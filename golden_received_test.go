@@ -0,0 +1,116 @@
+package golden_test
+
+import (
+	"github.com/franiglesias/golden"
+	"github.com/franiglesias/golden/internal/helper"
+	"github.com/franiglesias/golden/internal/vfs"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+/*
+TestReceived needs the same setup as TestVerify. Check it for documentation.
+*/
+func TestReceived(t *testing.T) {
+	var gld golden.Golden
+	var fs *vfs.MemFs
+	var tSpy helper.TSpy
+
+	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
+		fs = vfs.NewMemFs()
+		gld = *golden.NewUsingFs(fs)
+		tSpy = helper.TSpy{
+			T: t,
+		}
+	}
+
+	assertNoReceived := func(t *testing.T, name string) {
+		exists, err := fs.Exists(name)
+		assert.NoError(t, err)
+		assert.Falsef(t, exists, "received file %s exists", name)
+	}
+
+	t.Run("should keep received subject when verification fails", func(t *testing.T) {
+		setUp(t)
+		err := fs.WriteFile("testdata/TestReceived/should_keep_received_subject_when_verification_fails.snap", []byte("original subject."))
+		assert.NoError(t, err)
+
+		gld.Verify(&tSpy, "new subject.")
+		helper.AssertFailedTest(t, &tSpy)
+		vfs.AssertContentWasStored(t, fs, "testdata/TestReceived/should_keep_received_subject_when_verification_fails.received.snap", []byte("new subject."))
+		helper.AssertLogContains(t, &tSpy, "Received subject written to testdata/TestReceived/should_keep_received_subject_when_verification_fails.received.snap")
+	})
+
+	t.Run("should remove received subject when verification passes", func(t *testing.T) {
+		setUp(t)
+		err := fs.WriteFile("testdata/TestReceived/should_remove_received_subject_when_verification_passes.snap", []byte("original subject."))
+		assert.NoError(t, err)
+
+		gld.Verify(&tSpy, "new subject.")
+		helper.AssertFailedTest(t, &tSpy)
+
+		gld = *golden.NewUsingFs(fs)
+		tSpy.Reset()
+		gld.Verify(&tSpy, "original subject.")
+		helper.AssertPassTest(t, &tSpy)
+		assertNoReceived(t, "testdata/TestReceived/should_remove_received_subject_when_verification_passes.received.snap")
+	})
+
+	t.Run("should not create received file for new snapshots", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "some subject.")
+		helper.AssertPassTest(t, &tSpy)
+		assertNoReceived(t, "testdata/TestReceived/should_not_create_received_file_for_new_snapshots.received.snap")
+	})
+
+	t.Run("should not create received file of missing snapshot in strict mode", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "some subject.", golden.Strict())
+		helper.AssertFailedTest(t, &tSpy)
+		assertNoReceived(t, "testdata/TestReceived/should_not_create_received_file_of_missing_snapshot_in_strict_mode.received.snap")
+	})
+
+	t.Run("should remove received subject when snapshot is updated", func(t *testing.T) {
+		setUp(t)
+		err := fs.WriteFile("testdata/TestReceived/should_remove_received_subject_when_snapshot_is_updated.received.snap", []byte("new subject."))
+		assert.NoError(t, err)
+
+		gld.Verify(&tSpy, "new subject.", golden.WaitApproval())
+		helper.AssertFailedTest(t, &tSpy)
+		assertNoReceived(t, "testdata/TestReceived/should_remove_received_subject_when_snapshot_is_updated.received.snap")
+	})
+
+	t.Run("should use the snapshot extension", func(t *testing.T) {
+		setUp(t)
+		err := fs.WriteFile("testdata/custom.json", []byte("{}"))
+		assert.NoError(t, err)
+
+		gld.Verify(&tSpy, "[]", golden.Snapshot("custom"), golden.Extension(".json"))
+		helper.AssertFailedTest(t, &tSpy)
+		vfs.AssertContentWasStored(t, fs, "testdata/custom.received.json", []byte("[]"))
+	})
+
+	t.Run("should not write received subject in read-only mode", func(t *testing.T) {
+		setUp(t)
+		err := fs.WriteFile("testdata/TestReceived/should_not_write_received_subject_in_read-only_mode.snap", []byte("original subject."))
+		assert.NoError(t, err)
+
+		gld.Verify(&tSpy, "new subject.", golden.ReadOnly())
+		helper.AssertFailedTest(t, &tSpy)
+		assertNoReceived(t, "testdata/TestReceived/should_not_write_received_subject_in_read-only_mode.received.snap")
+	})
+
+	t.Run("should not report received files as obsolete", func(t *testing.T) {
+		setUp(t)
+		err := fs.WriteFile("testdata/TestReceived/should_not_report_received_files_as_obsolete.snap", []byte("original subject."))
+		assert.NoError(t, err)
+
+		gld.Verify(&tSpy, "new subject.")
+		obsolete, err := gld.Obsolete()
+		assert.NoError(t, err)
+		assert.Empty(t, obsolete)
+	})
+}
@@ -0,0 +1,125 @@
+package golden_test
+
+import (
+	"github.com/franiglesias/golden"
+	"github.com/franiglesias/golden/internal/helper"
+	"github.com/franiglesias/golden/internal/vfs"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+/*
+TestStrict needs the same setup as TestVerify. Check it for documentation.
+*/
+func TestStrict(t *testing.T) {
+	var gld golden.Golden
+	var fs *vfs.MemFs
+	var tSpy helper.TSpy
+
+	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
+		fs = vfs.NewMemFs()
+		gld = *golden.NewUsingFs(fs)
+		tSpy = helper.TSpy{
+			T: t,
+		}
+	}
+
+	assertNoSnapshot := func(t *testing.T, name string) {
+		exists, err := fs.Exists(name)
+		assert.NoError(t, err)
+		assert.Falsef(t, exists, "snapshot %s was created", name)
+	}
+
+	t.Run("should fail if snapshot doesn't exist", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "some subject.", golden.Strict())
+		helper.AssertFailedTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "testdata/TestStrict/should_fail_if_snapshot_doesn't_exist.snap doesn't exist")
+		helper.AssertReportContains(t, &tSpy, "+some subject.")
+		assertNoSnapshot(t, "testdata/TestStrict/should_fail_if_snapshot_doesn't_exist.snap")
+	})
+
+	t.Run("should verify existing snapshot", func(t *testing.T) {
+		setUp(t)
+		err := fs.WriteFile("testdata/TestStrict/should_verify_existing_snapshot.snap", []byte("some subject."))
+		assert.NoError(t, err)
+
+		gld.Verify(&tSpy, "some subject.", golden.Strict())
+		helper.AssertPassTest(t, &tSpy)
+
+		gld.Verify(&tSpy, "different subject.", golden.Strict(), golden.Snapshot("TestStrict/should_verify_existing_snapshot"))
+		helper.AssertFailedTest(t, &tSpy)
+	})
+
+	t.Run("should refuse approval mode", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "some subject.", golden.Strict(), golden.WaitApproval())
+		helper.AssertFatalTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "can't be approved")
+		assertNoSnapshot(t, "testdata/TestStrict/should_refuse_approval_mode.snap")
+	})
+
+	t.Run("should refuse update mode", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_UPDATE", "1")
+
+		gld.Verify(&tSpy, "some subject.", golden.Strict())
+		helper.AssertFatalTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "can't be updated")
+	})
+
+	t.Run("should be activated in CI if requested", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("CI", "true")
+
+		gld.Verify(&tSpy, "some subject.", golden.StrictInCI())
+		helper.AssertFailedTest(t, &tSpy)
+		assertNoSnapshot(t, "testdata/TestStrict/should_be_activated_in_CI_if_requested.snap")
+	})
+
+	t.Run("should not be activated in CI by default", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("CI", "true")
+
+		gld.Verify(&tSpy, "some subject.", golden.WaitApproval())
+		helper.AssertFailedTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "**Approval mode**")
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/TestStrict/should_not_be_activated_in_CI_by_default.snap")
+	})
+
+	t.Run("should not be activated out of CI if requested", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "some subject.", golden.StrictInCI())
+		helper.AssertPassTest(t, &tSpy)
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/TestStrict/should_not_be_activated_out_of_CI_if_requested.snap")
+	})
+
+	t.Run("should be activated by environment", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_STRICT", "1")
+
+		gld.Master(&tSpy, func(args ...any) any { return args[0] }, golden.Combine([]any{1}))
+		helper.AssertFailedTest(t, &tSpy)
+		assertNoSnapshot(t, "testdata/TestStrict/should_be_activated_by_environment.snap.json")
+	})
+
+	t.Run("should fail with empty inline snapshot", func(t *testing.T) {
+		setUp(t)
+
+		gld.VerifyInline(&tSpy, "some subject.", golden.Inline(""), golden.Strict())
+		helper.AssertFailedTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "+some subject.")
+	})
+
+	t.Run("should allow to be used as default", func(t *testing.T) {
+		setUp(t)
+
+		gld.Defaults(golden.Strict())
+		gld.Verify(&tSpy, "some subject.")
+		helper.AssertFailedTest(t, &tSpy)
+	})
+}
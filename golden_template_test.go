@@ -0,0 +1,82 @@
+package golden_test
+
+import (
+	"github.com/franiglesias/golden"
+	"github.com/franiglesias/golden/internal/helper"
+	"github.com/franiglesias/golden/internal/vfs"
+	"testing"
+)
+
+/*
+TestPathTemplate needs the same setup as TestVerify. Check it for documentation.
+*/
+func TestPathTemplate(t *testing.T) {
+	var gld golden.Golden
+	var fs *vfs.MemFs
+	var tSpy helper.TSpy
+
+	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
+		fs = vfs.NewMemFs()
+		gld = *golden.NewUsingFs(fs)
+		tSpy = helper.TSpy{
+			T: t,
+		}
+	}
+
+	t.Run("should resolve package test and subtest", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "subject", golden.PathTemplate("{package}/{test}/{subtest}{ext}"))
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/golden/TestPathTemplate/should_resolve_package_test_and_subtest.snap")
+	})
+
+	t.Run("should resolve file, index and format", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "first", golden.PathTemplate("{file}/{index}.{format}"))
+		gld.Verify(&tSpy, "second", golden.PathTemplate("{file}/{index}.{format}"))
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/golden_template/1.json")
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/golden_template/2.json")
+	})
+
+	t.Run("should number snapshots if template has no index", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "first", golden.PathTemplate("{test}{ext}"))
+		gld.Verify(&tSpy, "second", golden.PathTemplate("{test}{ext}"))
+		vfs.AssertContentWasStored(t, fs, "testdata/TestPathTemplate.snap", []byte("first"))
+		vfs.AssertContentWasStored(t, fs, "testdata/TestPathTemplate_2.snap", []byte("second"))
+	})
+
+	t.Run("should escape unsafe characters: like these?", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "subject", golden.PathTemplate("{test}/{subtest}{ext}"))
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/TestPathTemplate/should_escape_unsafe_characters%3A_like_these%3F.snap")
+	})
+
+	t.Run("should be resolved inside folder", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "subject", golden.Folder("__snapshots"), golden.PathTemplate("{package}/{test}{ext}"))
+		vfs.AssertSnapshotWasCreated(t, fs, "__snapshots/golden/TestPathTemplate.snap")
+	})
+
+	t.Run("should work as default", func(t *testing.T) {
+		setUp(t)
+
+		gld.Defaults(golden.PathTemplate("{package}/{subtest}{ext}"))
+		gld.Verify(&tSpy, "subject")
+		gld.Master(&tSpy, func(args ...any) any { return args[0] }, golden.Combine([]any{1}))
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/golden/should_work_as_default.snap")
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/golden/should_work_as_default.snap.json")
+	})
+
+	t.Run("should not apply to custom name", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "subject", golden.PathTemplate("{package}/{test}{ext}"), golden.Snapshot("custom"))
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/custom.snap")
+	})
+}
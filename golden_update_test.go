@@ -0,0 +1,105 @@
+package golden_test
+
+import (
+	"github.com/franiglesias/golden"
+	"github.com/franiglesias/golden/internal/helper"
+	"github.com/franiglesias/golden/internal/vfs"
+	"testing"
+)
+
+/*
+TestUpdate needs the same setup as TestVerify. Check it for documentation.
+
+Update mode is activated for the whole run, so we use t.Setenv to simulate
+GOLDEN_UPDATE=1 go test ./...
+*/
+func TestUpdate(t *testing.T) {
+	var gld golden.Golden
+	var fs *vfs.MemFs
+	var tSpy helper.TSpy
+
+	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
+		t.Setenv("GOLDEN_UPDATE", "1")
+		fs = vfs.NewMemFs()
+		gld = *golden.NewUsingFs(fs)
+		tSpy = helper.TSpy{
+			T: t,
+		}
+	}
+
+	t.Run("should create snapshot and pass", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "some subject.")
+		vfs.AssertContentWasStored(t, fs, "testdata/TestUpdate/should_create_snapshot_and_pass.snap", []byte("some subject."))
+		helper.AssertPassTest(t, &tSpy)
+		helper.AssertLogContains(t, &tSpy, "testdata/TestUpdate/should_create_snapshot_and_pass.snap created")
+	})
+
+	t.Run("should rewrite outdated snapshot and pass", func(t *testing.T) {
+		setUp(t)
+
+		err := fs.WriteFile("testdata/TestUpdate/should_rewrite_outdated_snapshot_and_pass.snap", []byte("original output."))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gld.Verify(&tSpy, "different output.")
+		vfs.AssertContentWasStored(t, fs, "testdata/TestUpdate/should_rewrite_outdated_snapshot_and_pass.snap", []byte("different output."))
+		helper.AssertPassTest(t, &tSpy)
+		helper.AssertLogContains(t, &tSpy, "testdata/TestUpdate/should_rewrite_outdated_snapshot_and_pass.snap updated")
+		helper.AssertLogContains(t, &tSpy, "-original output.\n+different output.\n")
+	})
+
+	t.Run("should report unchanged snapshot", func(t *testing.T) {
+		setUp(t)
+
+		err := fs.WriteFile("testdata/TestUpdate/should_report_unchanged_snapshot.snap", []byte("same output."))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gld.Verify(&tSpy, "same output.")
+		helper.AssertPassTest(t, &tSpy)
+		helper.AssertLogContains(t, &tSpy, "testdata/TestUpdate/should_report_unchanged_snapshot.snap unchanged")
+	})
+
+	t.Run("should keep approval mode when asked explicitly", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "some subject.", golden.WaitApproval())
+		helper.AssertFailedTest(t, &tSpy)
+	})
+
+	t.Run("should update golden master snapshot", func(t *testing.T) {
+		setUp(t)
+
+		err := fs.WriteFile("testdata/TestUpdate/should_update_golden_master_snapshot.snap.json", []byte("[]"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		f := func(args ...any) any {
+			return args[0].(int) * 2
+		}
+
+		gld.Master(&tSpy, f, golden.Combine([]any{1, 2}))
+		vfs.AssertSnapShotContains(t, fs, "testdata/TestUpdate/should_update_golden_master_snapshot.snap.json", `"Params": "2"`)
+		helper.AssertPassTest(t, &tSpy)
+	})
+
+	t.Run("should not update when mode is disabled", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_UPDATE", "0")
+
+		err := fs.WriteFile("testdata/TestUpdate/should_not_update_when_mode_is_disabled.snap", []byte("original output."))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gld.Verify(&tSpy, "different output.")
+		vfs.AssertContentWasStored(t, fs, "testdata/TestUpdate/should_not_update_when_mode_is_disabled.snap", []byte("original output."))
+		helper.AssertFailedTest(t, &tSpy)
+	})
+}
@@ -25,6 +25,7 @@ func TestVerify(t *testing.T) {
 	var tSpy helper.TSpy
 
 	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
 		// Passing t in each setup guarantees that we are using the right name for the
 		// snapshot, otherwise the name won't be accurate
 
@@ -41,6 +42,13 @@ func TestVerify(t *testing.T) {
 		}
 	}
 
+	// nextRun simulates running the test again: a new Golden instance that keeps
+	// the snapshots written in previous runs
+
+	nextRun := func() {
+		gld = *golden.NewUsingFs(fs)
+	}
+
 	t.Run("should create snapshot if not exists", func(t *testing.T) {
 		setUp(t)
 
@@ -60,6 +68,7 @@ func TestVerify(t *testing.T) {
 		setUp(t)
 
 		gld.Verify(&tSpy, "some output.")
+		nextRun()
 		gld.Verify(&tSpy, "different output.")
 
 		want := []byte(("some output."))
@@ -73,6 +82,7 @@ func TestVerify(t *testing.T) {
 		// Sets the snapshot for first time
 		gld.Verify(&tSpy, "original output.")
 		// Changes happened. Verify against existing snapshot
+		nextRun()
 		gld.Verify(&tSpy, "different output.")
 
 		helper.AssertFailedTest(t, &tSpy)
@@ -124,4 +134,78 @@ func TestVerify(t *testing.T) {
 		helper.AssertPassTest(t, &tSpy)
 		vfs.AssertSnapShotContains(t, fs, "testdata/TestVerify/should_scrub_data.snap", "<Current Time>")
 	})
+
+	t.Run("should number successive snapshots in the same test", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "first step.")
+		gld.Verify(&tSpy, "second step.")
+		gld.Verify(&tSpy, "third step.")
+
+		vfs.AssertContentWasStored(t, fs, "testdata/TestVerify/should_number_successive_snapshots_in_the_same_test.snap", []byte("first step."))
+		vfs.AssertContentWasStored(t, fs, "testdata/TestVerify/should_number_successive_snapshots_in_the_same_test_2.snap", []byte("second step."))
+		vfs.AssertContentWasStored(t, fs, "testdata/TestVerify/should_number_successive_snapshots_in_the_same_test_3.snap", []byte("third step."))
+	})
+
+	t.Run("should number only snapshots with the same path", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "first step.")
+		gld.Master(&tSpy, func(args ...any) any { return args[0] }, golden.Combine([]any{1}))
+		gld.Verify(&tSpy, "other folder.", golden.Folder("other"))
+		gld.Verify(&tSpy, "second step.")
+
+		name := "TestVerify/should_number_only_snapshots_with_the_same_path"
+		vfs.AssertContentWasStored(t, fs, "testdata/"+name+".snap", []byte("first step."))
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/"+name+".snap.json")
+		vfs.AssertContentWasStored(t, fs, "other/"+name+".snap", []byte("other folder."))
+		vfs.AssertContentWasStored(t, fs, "testdata/"+name+"_2.snap", []byte("second step."))
+	})
+
+	t.Run("should verify each step against its own snapshot", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "first step.")
+		gld.Verify(&tSpy, "second step.")
+
+		nextRun()
+		gld.Verify(&tSpy, "first step.")
+		gld.Verify(&tSpy, "second step.")
+		helper.AssertPassTest(t, &tSpy)
+
+		nextRun()
+		gld.Verify(&tSpy, "first step.")
+		gld.Verify(&tSpy, "changed step.")
+		helper.AssertFailedTest(t, &tSpy)
+		helper.AssertReportContains(t, &tSpy, "-second step.\n+changed step.\n")
+	})
+
+	t.Run("should not number snapshots with custom name", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "first step.")
+		gld.Verify(&tSpy, "custom step.", golden.Snapshot("custom_step"))
+		gld.Verify(&tSpy, "second step.")
+
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/custom_step.snap")
+		vfs.AssertContentWasStored(t, fs, "testdata/TestVerify/should_not_number_snapshots_with_custom_name_2.snap", []byte("second step."))
+	})
+
+	t.Run("should use custom layout", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "first step.", golden.Layout(golden.PackageLayout{}))
+		gld.Verify(&tSpy, "second step.", golden.Layout(golden.PackageLayout{}))
+
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/golden/TestVerify__should_use_custom_layout.snap")
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/golden/TestVerify__should_use_custom_layout_2.snap")
+	})
+
+	t.Run("should not apply layout to custom name", func(t *testing.T) {
+		setUp(t)
+
+		gld.Verify(&tSpy, "original output", golden.Snapshot("custom:snapshot"), golden.Layout(golden.FlatLayout{}))
+
+		vfs.AssertSnapshotWasCreated(t, fs, "testdata/custom:snapshot.snap")
+	})
 }
@@ -0,0 +1,140 @@
+package golden
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/franiglesias/golden/internal/hunk"
+	"html/template"
+	"path/filepath"
+)
+
+/*
+writeHTMLReport writes a self-contained HTML page with the differences of all
+the failed verifications of the run. Links to snapshots are relative to the
+report.
+*/
+func (g *Golden) writeHTMLReport(path string) error {
+	failed := g.outcomes.failed()
+	page := htmlPage{Failures: make([]htmlFailure, len(failed))}
+	for i, result := range failed {
+		page.Failures[i] = htmlFailure{
+			ID:   fmt.Sprintf("failure-%d", i+1),
+			Test: result.test,
+			Path: result.path,
+			Link: relativeLink(path, result.file),
+			Mode: result.mode,
+			Rows: htmlRows(result.want, result.got),
+		}
+	}
+
+	var b bytes.Buffer
+	err := htmlReport.Execute(&b, page)
+	if err != nil {
+		return err
+	}
+	return g.fs.WriteFile(path, b.Bytes())
+}
+
+/*
+relativeLink returns the path of file relative to the folder of the report
+*/
+func relativeLink(report string, file string) string {
+	dir, err := filepath.Abs(filepath.Dir(report))
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	target, err := filepath.Abs(file)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	link, err := filepath.Rel(dir, target)
+	if err != nil {
+		return filepath.ToSlash(target)
+	}
+	return filepath.ToSlash(link)
+}
+
+type htmlPage struct {
+	Failures []htmlFailure
+}
+
+type htmlFailure struct {
+	ID   string
+	Test string
+	Path string
+	Link string
+	Mode string
+	Rows []htmlRow
+}
+
+type htmlRow struct {
+	Class string
+	Mark  string
+	Left  string
+	Right string
+}
+
+var htmlRowClasses = map[rune]string{
+	' ': "equal",
+	'|': "changed",
+	'<': "deleted",
+	'>': "inserted",
+}
+
+func htmlRows(want, got string) []htmlRow {
+	var rows []htmlRow
+	for _, row := range sideBySideRows(hunk.Compute(want, got).Lines()) {
+		rows = append(rows, htmlRow{
+			Class: htmlRowClasses[row.mark],
+			Mark:  string(row.mark),
+			Left:  row.left,
+			Right: row.right,
+		})
+	}
+	return rows
+}
+
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Golden report</title>
+<style>
+body { font-family: sans-serif; margin: 0; display: flex; }
+nav { width: 20em; height: 100vh; overflow: auto; position: sticky; top: 0; background: #f4f4f4; padding: 1em; box-sizing: border-box; }
+nav ol { padding-left: 1.5em; }
+nav a { word-break: break-all; }
+main { flex: 1; padding: 1em; overflow: auto; }
+section { margin-bottom: 2em; }
+h2 { font-size: 1.1em; word-break: break-all; }
+.mode { font-size: 0.8em; background: #ddd; border-radius: 3px; padding: 0 0.4em; }
+table { border-collapse: collapse; width: 100%; font-family: monospace; white-space: pre-wrap; }
+td { vertical-align: top; padding: 0 0.5em; border-left: 1px solid #ccc; width: 50%; }
+td.mark { width: 1em; text-align: center; }
+th { text-align: left; padding: 0 0.5em; }
+tr.changed td, tr.deleted td.want { background: #fdd; }
+tr.changed td.got, tr.inserted td.got { background: #dfd; }
+</style>
+</head>
+<body>
+<nav>
+<h1>Golden report</h1>
+{{if .Failures}}<p>{{len .Failures}} failed verifications</p>
+<ol>
+{{range .Failures}}<li><a href="#{{.ID}}">{{.Test}}</a></li>
+{{end}}</ol>
+{{else}}<p>No failed verifications.</p>
+{{end}}</nav>
+<main>
+{{range .Failures}}<section id="{{.ID}}">
+<h2>{{.Test}} <span class="mode">{{.Mode}}</span></h2>
+<p><a href="{{.Link}}">{{.Path}}</a></p>
+<table>
+<tr><th>Snapshot</th><th></th><th>Subject</th></tr>
+{{range .Rows}}<tr class="{{.Class}}"><td class="want">{{.Left}}</td><td class="mark">{{.Mark}}</td><td class="got">{{.Right}}</td></tr>
+{{end}}</table>
+</section>
+{{end}}</main>
+</body>
+</html>
+`))
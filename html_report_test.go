@@ -0,0 +1,91 @@
+package golden
+
+import (
+	"bytes"
+	"github.com/franiglesias/golden/internal/helper"
+	"github.com/franiglesias/golden/internal/vfs"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHTMLReport(t *testing.T) {
+	var fs *vfs.MemFs
+	var g *Golden
+	var tSpy helper.TSpy
+
+	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
+		fs = vfs.NewMemFs()
+		g = NewUsingFs(fs)
+		tSpy = helper.TSpy{T: t}
+	}
+
+	report := func(t *testing.T) string {
+		content, err := fs.ReadFile("reports/golden.html")
+		assert.NoError(t, err)
+		return string(content)
+	}
+
+	t.Run("should report failed verifications", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_HTML_REPORT", "reports/golden.html")
+		_ = fs.WriteFile("testdata/failing.snap", []byte("name: Fran\n<b>bold</b>\n"))
+		_ = fs.WriteFile("testdata/passing.snap", []byte("same"))
+
+		g.Verify(&tSpy, "name: Frank\n<b>bold</b>\n", Snapshot("failing"))
+		g.Verify(&tSpy, "same", Snapshot("passing"))
+		out := bytes.Buffer{}
+		g.writeReports(&out)
+
+		assert.Equal(t, "golden: HTML report written to reports/golden.html\n", out.String())
+		html := report(t)
+		assert.Contains(t, html, "<p>1 failed verifications</p>")
+		assert.Contains(t, html, `<li><a href="#failure-1">TestHTMLReport/should_report_failed_verifications</a></li>`)
+		assert.Contains(t, html, `<a href="../testdata/failing.snap">testdata/failing.snap</a>`)
+		assert.Contains(t, html, `<span class="mode">verify</span>`)
+		assert.Contains(t, html, `<tr class="changed"><td class="want">name: Fran</td><td class="mark">|</td><td class="got">name: Frank</td></tr>`)
+		assert.Contains(t, html, `<td class="want">&lt;b&gt;bold&lt;/b&gt;</td>`)
+		assert.NotContains(t, html, "passing.snap")
+	})
+
+	t.Run("should report approvals", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_HTML_REPORT", "reports/golden.html")
+
+		g.Verify(&tSpy, "approved", Snapshot("approved"), WaitApproval())
+		g.writeReports(&bytes.Buffer{})
+
+		html := report(t)
+		assert.Contains(t, html, `<span class="mode">approval</span>`)
+		assert.Contains(t, html, `<tr class="inserted"><td class="want"></td><td class="mark">&gt;</td><td class="got">approved</td></tr>`)
+	})
+
+	t.Run("should write empty report without failures", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_HTML_REPORT", "reports/golden.html")
+
+		g.writeReports(&bytes.Buffer{})
+
+		assert.Contains(t, report(t), "No failed verifications.")
+	})
+
+	t.Run("should not write report if not requested", func(t *testing.T) {
+		setUp(t)
+		out := bytes.Buffer{}
+
+		g.writeReports(&out)
+
+		assert.Empty(t, out.String())
+		exists, _ := fs.Exists("reports/golden.html")
+		assert.False(t, exists)
+	})
+
+	t.Run("should be written by Main even if tests fail", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_HTML_REPORT", "reports/golden.html")
+
+		g.Main(&runnerStub{code: 1})
+
+		vfs.AssertSnapshotWasCreated(t, fs, "reports/golden.html")
+	})
+}
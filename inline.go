@@ -0,0 +1,287 @@
+package golden
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+/*
+InlineSnapshot holds the expected value of a VerifyInline call. Create it with
+Inline.
+*/
+type InlineSnapshot struct {
+	value string
+}
+
+/*
+Inline wraps the expected value for VerifyInline. Leave it empty and golden
+will write the current subject in its place.
+
+	golden.VerifyInline(t, subject, golden.Inline(``))
+
+One leading and one trailing line break are ignored, so you can write
+multi-line snapshots in a readable way. Any other line break is part of the
+expected value.
+*/
+func Inline(snapshot string) InlineSnapshot {
+	return InlineSnapshot{value: snapshot}
+}
+
+/*
+String returns the expected value without the line breaks that delimit it. See
+inlineLiteral.
+*/
+func (i InlineSnapshot) String() string {
+	return strings.TrimSuffix(strings.TrimPrefix(i.value, "\n"), "\n")
+}
+
+/*
+lineShift records that a rewrite at line changed the number of lines of the
+file. Line numbers from the runtime refer to the source as it was compiled, so
+we need them to locate calls after other rewrites in the same file.
+*/
+type lineShift struct {
+	line  int
+	delta int
+}
+
+/*
+inlineEdits keeps the rewrites done in the run: the line shifts of each file,
+and the subject written at each call, so a call in a loop can't overwrite the
+value written by a previous iteration
+*/
+type inlineEdits struct {
+	sync.Mutex
+	shifts  map[string][]lineShift
+	written map[sourceLocation]string
+}
+
+func newInlineEdits() *inlineEdits {
+	return &inlineEdits{
+		shifts:  make(map[string][]lineShift),
+		written: make(map[sourceLocation]string),
+	}
+}
+
+func (e *inlineEdits) currentLine(loc sourceLocation) int {
+	line := loc.line
+	for _, shift := range e.shifts[loc.file] {
+		if shift.line < loc.line {
+			line += shift.delta
+		}
+	}
+	return line
+}
+
+func (e *inlineEdits) record(loc sourceLocation, subject string, delta int) {
+	e.written[loc] = subject
+	if delta == 0 {
+		return
+	}
+	e.shifts[loc.file] = append(e.shifts[loc.file], lineShift{line: loc.line, delta: delta})
+}
+
+/*
+VerifyInline compares the subject with the expected value passed with Inline,
+instead of using a snapshot file. It uses the same normalizer, scrubbers and
+reporter than Verify.
+
+If the expected value is empty, it is written in the test source with the
+current subject, and the test passes. In approval and update modes, the value
+is rewritten in the test source when it is outdated.
+*/
+func (g *Golden) VerifyInline(t Failable, s any, snapshot InlineSnapshot, options ...Option) {
+	t.Helper()
+
+	conf := g.global
+	for _, option := range options {
+		option(&conf)
+	}
+	conf.format = g.format()
+
+	loc, err := callerLocation()
+	if err != nil {
+		fatalf(t, errorHeader, "inline", s, err)
+		return
+	}
+
+	launch := diffTools.hold(t)
+	defer launch()
+	unlock := g.locks.lock(loc.file)
+	defer unlock()
+
+	subject, err := g.normalize(s, conf.scrubbers)
+	if err != nil {
+		fatalf(t, errorHeader, loc, s, err)
+		return
+	}
+
+	previous := snapshot.String()
+
+	requested, err := approvalRequested(t.Name())
+	if err != nil {
+		fatalf(t, errorHeader, loc, s, err)
+		return
+	}
+	conf.approve = conf.approve || requested
+
+	if refusal := conf.strictRefusal(); refusal != "" {
+		fatalf(t, refusal, loc)
+		return
+	}
+
+	session := g.session(conf)
+
+	record := func(mode string, passed bool) {
+		g.outcomes.record(newOutcome(t, loc.String(), loc.file, mode, passed, previous, subject))
+	}
+
+	switch {
+	case conf.approvalMode():
+		err = session.rewriteInline(loc, subject)
+		if err == nil {
+			t.Errorf(approvalHeader, conf.differences(t, modeApproval, loc.String(), previous, subject))
+			record(modeApproval, false)
+		}
+	case previous == "" && conf.strictMode():
+		t.Errorf(strictMissing, loc, conf.differences(t, modeStrict, loc.String(), "", subject))
+		record(modeStrict, false)
+	case previous == "":
+		err = session.rewriteInline(loc, subject)
+		if err == nil && updateRequested() {
+			logf(t, updateCreated, loc)
+		}
+		if err == nil {
+			record(modeCreated, true)
+		}
+	case previous == subject && updateRequested():
+		logf(t, updateUnchanged, loc)
+		record(modeUpdate, true)
+	case previous == subject:
+		record(modeVerify, true)
+	case updateRequested():
+		err = session.rewriteInline(loc, subject)
+		if err == nil {
+			logf(t, updateUpdated, loc, conf.differences(t, modeUpdate, loc.String(), previous, subject))
+			record(modeUpdate, true)
+		}
+	default:
+		t.Errorf(verifyHeader, conf.differences(t, modeVerify, loc.String(), previous, subject))
+		record(modeVerify, false)
+	}
+
+	if err != nil {
+		fatalf(t, errorHeader, loc, s, err)
+	}
+}
+
+/*
+rewriteInline replaces the argument of the Inline call in the VerifyInline
+call found at loc with the subject
+*/
+func (g *Golden) rewriteInline(loc sourceLocation, subject string) error {
+	g.inline.Lock()
+	defer g.inline.Unlock()
+
+	if written, ok := g.inline.written[loc]; ok {
+		if written != subject {
+			return fmt.Errorf("Inline at %s was already rewritten in this run with another subject, %q: use Verify for calls in loops", loc, written)
+		}
+		return nil
+	}
+
+	source, err := g.fs.ReadFile(loc.file)
+	if err != nil {
+		return fmt.Errorf("could not read test source: %w", err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, loc.file, source, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("could not parse test source: %w", err)
+	}
+
+	lit := findInlineLiteral(fset, file, g.inline.currentLine(loc))
+	if lit == nil {
+		return errors.New("could not find golden.Inline() in the VerifyInline call")
+	}
+
+	previousLines := strings.Count(lit.Value, "\n")
+	lit.Value = inlineLiteral(subject)
+	lit.Kind = token.STRING
+
+	var updated bytes.Buffer
+	err = format.Node(&updated, fset, file)
+	if err != nil {
+		return fmt.Errorf("could not print test source: %w", err)
+	}
+
+	err = g.fs.WriteFile(loc.file, updated.Bytes())
+	if err != nil {
+		return fmt.Errorf("could not write test source: %w", err)
+	}
+
+	g.inline.record(loc, subject, strings.Count(lit.Value, "\n")-previousLines)
+	return nil
+}
+
+/*
+findInlineLiteral looks for the innermost VerifyInline call that spans line
+and returns the argument of its Inline call
+*/
+func findInlineLiteral(fset *token.FileSet, file *ast.File, line int) *ast.BasicLit {
+	var found *ast.BasicLit
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || funcName(call) != "VerifyInline" {
+			return true
+		}
+		if fset.Position(call.Pos()).Line > line || fset.Position(call.End()).Line < line {
+			return true
+		}
+		for _, arg := range call.Args {
+			inline, ok := arg.(*ast.CallExpr)
+			if !ok || funcName(inline) != "Inline" || len(inline.Args) != 1 {
+				continue
+			}
+			if lit, ok := inline.Args[0].(*ast.BasicLit); ok {
+				found = lit
+			}
+		}
+		return true
+	})
+	return found
+}
+
+func funcName(call *ast.CallExpr) string {
+	switch f := call.Fun.(type) {
+	case *ast.Ident:
+		return f.Name
+	case *ast.SelectorExpr:
+		return f.Sel.Name
+	}
+	return ""
+}
+
+/*
+inlineLiteral uses a raw string when possible, so the snapshot is readable.
+Multi-line subjects get a line break before and after them, so they start in
+their own line. InlineSnapshot.String removes exactly those line breaks.
+*/
+func inlineLiteral(subject string) string {
+	if strings.Contains(subject, "\n") {
+		subject = "\n" + subject + "\n"
+	}
+	if strings.ContainsAny(subject, "`\r") {
+		return strconv.Quote(subject)
+	}
+	return "`" + subject + "`"
+}
@@ -0,0 +1,33 @@
+package golden
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestInlineLiteral(t *testing.T) {
+	examples := []struct {
+		name    string
+		subject string
+		literal string
+	}{
+		{name: "single line", subject: "one line", literal: "`one line`"},
+		{name: "multi-line", subject: "one\ntwo", literal: "`\none\ntwo\n`"},
+		{name: "trailing line break", subject: "one\ntwo\n", literal: "`\none\ntwo\n\n`"},
+		{name: "leading line break", subject: "\none\ntwo", literal: "`\n\none\ntwo\n`"},
+		{name: "only a line break", subject: "\n", literal: "`\n\n\n`"},
+		{name: "quoted", subject: "uses `backticks`", literal: "\"uses `backticks`\""},
+		{name: "quoted multi-line", subject: "\nuses `backticks`\n", literal: "\"\\n\\nuses `backticks`\\n\\n\""},
+	}
+	for _, example := range examples {
+		t.Run(example.name, func(t *testing.T) {
+			literal := inlineLiteral(example.subject)
+			assert.Equal(t, example.literal, literal)
+
+			value, err := strconv.Unquote(literal)
+			assert.NoError(t, err)
+			assert.Equal(t, example.subject, Inline(value).String())
+		})
+	}
+}
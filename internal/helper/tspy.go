@@ -1,7 +1,9 @@
 package helper
 
 import (
+	"fmt"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -13,17 +15,46 @@ snapshot are found
 type TSpy struct {
 	*testing.T
 	failed bool
+	fatal  bool
 	report string
+	logs   []string
 }
 
-func (t *TSpy) Errorf(_ string, report ...any) {
+func (t *TSpy) Errorf(format string, args ...any) {
 	t.failed = true
-	t.report = report[0].(string)
+	t.report = fmt.Sprintf(format, args...)
+}
+
+/*
+Fatalf records the failure but, unlike testing.T, doesn't stop the test, so we
+can inspect the report
+*/
+func (t *TSpy) Fatalf(format string, args ...any) {
+	t.failed = true
+	t.fatal = true
+	t.report = fmt.Sprintf(format, args...)
+}
+
+func (t *TSpy) Logf(format string, args ...any) {
+	t.logs = append(t.logs, fmt.Sprintf(format, args...))
 }
 
 func (t *TSpy) Reset() {
 	t.failed = false
+	t.fatal = false
 	t.report = ""
+	t.logs = nil
+}
+
+/*
+ClearModes unsets the environment variables that activate modes for the whole
+run, like GOLDEN_UPDATE, until the test ends. Tests activate the modes they
+need explicitly.
+*/
+func ClearModes(t *testing.T) {
+	for _, env := range []string{"CI", "GOLDEN_STRICT", "GOLDEN_UPDATE", "GOLDEN_PRUNE", "GOLDEN_READONLY", "GOLDEN_APPROVE", "GOLDEN_HTML_REPORT", "GOLDEN_JSON_REPORT", "GOLDEN_DIFFTOOL"} {
+		t.Setenv(env, "")
+	}
 }
 
 /*
@@ -33,6 +64,10 @@ func AssertFailedTest(t *testing.T, gt *TSpy) {
 	assert.True(t, gt.failed, "Test passed and it shouldn't")
 }
 
+func AssertFatalTest(t *testing.T, gt *TSpy) {
+	assert.True(t, gt.fatal, "Test was not stopped and it should")
+}
+
 func AssertPassTest(t *testing.T, gt *TSpy) {
 	assert.False(t, gt.failed, "Test failed and it shouldn't")
 }
@@ -40,3 +75,12 @@ func AssertPassTest(t *testing.T, gt *TSpy) {
 func AssertReportContains(t *testing.T, g *TSpy, s string) {
 	assert.Containsf(t, g.report, s, "Diff report doesn't contains expected '%s'", s)
 }
+
+func AssertLogContains(t *testing.T, g *TSpy, s string) {
+	for _, log := range g.logs {
+		if strings.Contains(log, s) {
+			return
+		}
+	}
+	assert.Failf(t, "Log doesn't contain expected message", "'%s' not found in %v", s, g.logs)
+}
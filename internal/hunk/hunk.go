@@ -0,0 +1,209 @@
+package hunk
+
+import (
+	"fmt"
+	"github.com/andreyvit/diff"
+	"strings"
+)
+
+const contextLines = 3
+
+/*
+Diff holds the line diff between a snapshot and a subject, as produced for
+LineDiffReporter, grouped in hunks: runs of consecutive changed lines. Each
+hunk can be accepted or rejected independently with Merge.
+*/
+type Diff struct {
+	want  string
+	got   string
+	lines []string
+	hunks []span
+}
+
+type span struct {
+	start int
+	end   int
+}
+
+/*
+Hunk is a group of consecutive changed lines, with some unchanged lines around
+for context. Lines are prefixed with " ", "-" or "+", like in a unified diff.
+Line and SubjectLine are the numbers, starting at 1, of the first changed line
+in the snapshot and in the subject.
+*/
+type Hunk struct {
+	Line        int
+	SubjectLine int
+	Before      []string
+	Changes     []string
+	After       []string
+}
+
+func (h Hunk) String() string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "@@ line %d @@\n", h.Line)
+	for _, group := range [][]string{h.Before, h.Changes, h.After} {
+		for _, line := range group {
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+/*
+Compute returns the diff between want and got
+*/
+func Compute(want, got string) Diff {
+	d := Diff{want: want, got: got}
+	if want == got {
+		return d
+	}
+	d.lines = diff.LineDiffAsLines(want, got)
+	for i := 0; i < len(d.lines); i++ {
+		if changed(d.lines[i]) {
+			s := span{start: i}
+			for i < len(d.lines) && changed(d.lines[i]) {
+				i++
+			}
+			s.end = i
+			d.hunks = append(d.hunks, s)
+		}
+	}
+	return d
+}
+
+func changed(line string) bool {
+	return strings.HasPrefix(line, "-") || strings.HasPrefix(line, "+")
+}
+
+/*
+Hunks returns the hunks of the diff, in order, with three lines of context
+*/
+func (d Diff) Hunks() []Hunk {
+	return d.HunksWithContext(contextLines)
+}
+
+/*
+HunksWithContext returns the hunks of the diff, in order, with up to lines
+unchanged lines of context before and after the changes
+*/
+func (d Diff) HunksWithContext(lines int) []Hunk {
+	if lines < 0 {
+		lines = 0
+	}
+	hunks := make([]Hunk, len(d.hunks))
+	for i, s := range d.hunks {
+		before := s.start - lines
+		if before < 0 {
+			before = 0
+		}
+		after := s.end + lines
+		if after > len(d.lines) {
+			after = len(d.lines)
+		}
+		hunks[i] = Hunk{
+			Line:        d.line(s.start, "+"),
+			SubjectLine: d.line(s.start, "-"),
+			Before:      context(d.lines[before:s.start]),
+			Changes:     d.lines[s.start:s.end],
+			After:       context(d.lines[s.end:after]),
+		}
+	}
+	return hunks
+}
+
+/*
+Lines returns all the lines of the diff, prefixed like the lines of the hunks
+*/
+func (d Diff) Lines() []string {
+	return d.lines
+}
+
+/*
+line returns the number of the line at position i of the diff, starting at 1,
+skipping the lines with the prefix of the other side
+*/
+func (d Diff) line(i int, other string) int {
+	line := 1
+	for _, l := range d.lines[:i] {
+		if !strings.HasPrefix(l, other) {
+			line++
+		}
+	}
+	return line
+}
+
+/*
+context keeps only the unchanged lines closest to the hunk, because the lines
+around it may belong to other hunks
+*/
+func context(lines []string) []string {
+	var ctx []string
+	for _, line := range lines {
+		if changed(line) {
+			ctx = nil
+			continue
+		}
+		ctx = append(ctx, line)
+	}
+	return ctx
+}
+
+/*
+Merge returns the snapshot with the changes of the accepted hunks applied.
+accepted has the positions of the hunks, starting at 0. Positions out of range
+are ignored.
+*/
+func (d Diff) Merge(accepted []int) string {
+	accept := make(map[int]bool)
+	for _, i := range accepted {
+		if i >= 0 && i < len(d.hunks) {
+			accept[i] = true
+		}
+	}
+	if len(accept) == 0 {
+		return d.want
+	}
+	if len(accept) == len(d.hunks) {
+		return d.got
+	}
+
+	var merged []string
+	trailingNewline := strings.HasSuffix(d.want, "\n")
+	h := 0
+	for i := 0; i < len(d.lines); {
+		if h < len(d.hunks) && i == d.hunks[h].start {
+			keep := "-"
+			if accept[h] {
+				keep = "+"
+			}
+			for _, line := range d.lines[d.hunks[h].start:d.hunks[h].end] {
+				if strings.HasPrefix(line, keep) {
+					merged = append(merged, line[1:])
+				}
+			}
+			i = d.hunks[h].end
+			if i == len(d.lines) && accept[h] {
+				trailingNewline = strings.HasSuffix(d.got, "\n")
+			}
+			h++
+			continue
+		}
+		merged = append(merged, d.lines[i][1:])
+		i++
+	}
+
+	result := strings.Join(merged, "\n")
+	if trailingNewline {
+		result += "\n"
+	}
+	return result
+}
+
+/*
+Len returns the number of hunks
+*/
+func (d Diff) Len() int {
+	return len(d.hunks)
+}
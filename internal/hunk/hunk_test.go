@@ -0,0 +1,148 @@
+package hunk
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const snapshot = `Invoice 1234
+Customer: Fran
+Date: 2024-01-01
+Item: Book
+Item: Pen
+Item: Notebook
+Subtotal: 20
+Tax: 4
+Total: 24
+`
+
+const subject = `Invoice 1234
+Customer: Frank
+Date: 2024-01-01
+Item: Book
+Item: Pen
+Item: Notebook
+Subtotal: 20
+Tax: 4
+Total: 25
+Paid: yes
+`
+
+func TestDiff(t *testing.T) {
+	t.Run("should find no hunks in equal texts", func(t *testing.T) {
+		d := Compute(snapshot, snapshot)
+		assert.Equal(t, 0, d.Len())
+		assert.Empty(t, d.Hunks())
+		assert.Equal(t, snapshot, d.Merge(nil))
+	})
+
+	t.Run("should group changed lines in hunks", func(t *testing.T) {
+		d := Compute(snapshot, subject)
+		assert.Equal(t, 2, d.Len())
+
+		hunks := d.Hunks()
+		assert.Equal(t, Hunk{
+			Line:        2,
+			SubjectLine: 2,
+			Before:      []string{" Invoice 1234"},
+			Changes:     []string{"-Customer: Fran", "+Customer: Frank"},
+			After:       []string{" Date: 2024-01-01", " Item: Book", " Item: Pen"},
+		}, hunks[0])
+		assert.Equal(t, Hunk{
+			Line:        9,
+			SubjectLine: 9,
+			Before:      []string{" Item: Notebook", " Subtotal: 20", " Tax: 4"},
+			Changes:     []string{"-Total: 24", "+Total: 25", "+Paid: yes"},
+		}, hunks[1])
+	})
+
+	t.Run("should number lines in snapshot and subject", func(t *testing.T) {
+		d := Compute("one\ntwo\nthree\nfour\n", "zero\none\ntwo\nTHREE\nfour\n")
+
+		hunks := d.HunksWithContext(1)
+		assert.Equal(t, Hunk{
+			Line:        1,
+			SubjectLine: 1,
+			Changes:     []string{"+zero"},
+			After:       []string{" one"},
+		}, hunks[0])
+		assert.Equal(t, Hunk{
+			Line:        3,
+			SubjectLine: 4,
+			Before:      []string{" two"},
+			Changes:     []string{"-three", "+THREE"},
+			After:       []string{" four"},
+		}, hunks[1])
+	})
+
+	t.Run("should diff long texts", func(t *testing.T) {
+		var want, got strings.Builder
+		for i := 0; i < 70000; i++ {
+			_, _ = fmt.Fprintf(&want, "line %d\n", i)
+			if i != 1500 {
+				_, _ = fmt.Fprintf(&got, "line %d\n", i)
+			}
+		}
+
+		hunks := Compute(want.String(), got.String()).Hunks()
+		assert.Len(t, hunks, 1)
+		assert.Equal(t, []string{"-line 1500"}, hunks[0].Changes)
+	})
+
+	t.Run("should render hunk", func(t *testing.T) {
+		d := Compute(snapshot, subject)
+		expected := "@@ line 9 @@\n Item: Notebook\n Subtotal: 20\n Tax: 4\n-Total: 24\n+Total: 25\n+Paid: yes\n"
+		assert.Equal(t, expected, d.Hunks()[1].String())
+	})
+
+	t.Run("should merge accepted hunks", func(t *testing.T) {
+		d := Compute(snapshot, subject)
+
+		assert.Equal(t, snapshot, d.Merge(nil))
+		assert.Equal(t, subject, d.Merge([]int{0, 1}))
+		assert.Equal(t, `Invoice 1234
+Customer: Frank
+Date: 2024-01-01
+Item: Book
+Item: Pen
+Item: Notebook
+Subtotal: 20
+Tax: 4
+Total: 24
+`, d.Merge([]int{0}))
+		assert.Equal(t, `Invoice 1234
+Customer: Fran
+Date: 2024-01-01
+Item: Book
+Item: Pen
+Item: Notebook
+Subtotal: 20
+Tax: 4
+Total: 25
+Paid: yes
+`, d.Merge([]int{1}))
+	})
+
+	t.Run("should ignore hunks out of range", func(t *testing.T) {
+		d := Compute(snapshot, subject)
+
+		assert.Equal(t, snapshot, d.Merge([]int{2, -1}))
+	})
+
+	t.Run("should keep trailing newline of accepted last hunk", func(t *testing.T) {
+		d := Compute("a\nb\nc\nd\ne\nf\ng", "A\nb\nc\nd\ne\nf\ng\n")
+		assert.Equal(t, 2, d.Len())
+
+		assert.Equal(t, "a\nb\nc\nd\ne\nf\ng\n", d.Merge([]int{1}))
+		assert.Equal(t, "A\nb\nc\nd\ne\nf\ng", d.Merge([]int{0}))
+	})
+
+	t.Run("should handle new snapshots", func(t *testing.T) {
+		d := Compute("", "a\nb\n")
+		assert.Equal(t, 1, d.Len())
+		assert.Equal(t, 1, d.Hunks()[0].Line)
+		assert.Equal(t, "a\nb\n", d.Merge([]int{0}))
+	})
+}
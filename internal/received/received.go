@@ -0,0 +1,55 @@
+/*
+Package received names the files that keep the subject of a failed
+verification next to its snapshot. Both the library and the golden command use
+it, so they always agree on these names.
+*/
+package received
+
+import (
+	"path"
+	"strings"
+)
+
+/*
+Mark goes right before the extension of the snapshot
+*/
+const Mark = ".received"
+
+/*
+Path is the path of the received file of a snapshot:
+
+	testdata/TestSomething.snap -> testdata/TestSomething.received.snap
+*/
+func Path(snapshot string, ext string) string {
+	if ext != "" && strings.HasSuffix(snapshot, ext) {
+		return strings.TrimSuffix(snapshot, ext) + Mark + ext
+	}
+	return snapshot + Mark
+}
+
+/*
+Snapshot returns the snapshot that a received file belongs to. The mark must
+be right before the extension, or at the end of the name:
+
+	testdata/TestSomething.received.snap -> testdata/TestSomething.snap
+*/
+func Snapshot(received string) (string, bool) {
+	dir, base := path.Split(received)
+	i := strings.LastIndex(base, Mark)
+	if i <= 0 {
+		return "", false
+	}
+	rest := base[i+len(Mark):]
+	if rest != "" && !strings.HasPrefix(rest, ".") {
+		return "", false
+	}
+	return dir + base[:i] + rest, true
+}
+
+/*
+Is returns true if the file is a received file
+*/
+func Is(name string) bool {
+	_, ok := Snapshot(name)
+	return ok
+}
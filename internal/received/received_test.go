@@ -0,0 +1,35 @@
+package received
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	assert.Equal(t, "testdata/TestSomething.received.snap", Path("testdata/TestSomething.snap", ".snap"))
+	assert.Equal(t, "testdata/TestSomething.received.snap.json", Path("testdata/TestSomething.snap.json", ".snap.json"))
+	assert.Equal(t, "testdata/TestSomething.golden.received", Path("testdata/TestSomething.golden", ".snap"))
+}
+
+func TestSnapshot(t *testing.T) {
+	examples := []struct {
+		received string
+		snapshot string
+		ok       bool
+	}{
+		{"testdata/TestSomething.received.snap", "testdata/TestSomething.snap", true},
+		{"testdata/TestSomething.received.snap.json", "testdata/TestSomething.snap.json", true},
+		{"testdata/TestSomething.received", "testdata/TestSomething", true},
+		{"testdata/TestSomething.snap", "", false},
+		{"testdata/TestSomething.receivedsnap", "", false},
+		{"testdata/Test.receivedOrders.snap", "", false},
+		{"testdata/Test.receivedOrders/some_case.snap", "", false},
+		{"testdata/.received.snap", "", false},
+	}
+	for _, example := range examples {
+		snapshot, ok := Snapshot(example.received)
+		assert.Equal(t, example.ok, ok, example.received)
+		assert.Equal(t, example.snapshot, snapshot, example.received)
+		assert.Equal(t, example.ok, Is(example.received), example.received)
+	}
+}
@@ -0,0 +1,56 @@
+package vfs
+
+/*
+FailingFs is an in-memory filesystem that returns the configured errors, so we
+can test how golden manages problems with the snapshots. Operations without a
+configured error work as in MemFs.
+*/
+type FailingFs struct {
+	*MemFs
+	ExistsError error
+	WriteError  error
+	ReadError   error
+	ListError   error
+	RemoveError error
+}
+
+func NewFailingFs() *FailingFs {
+	return &FailingFs{
+		MemFs: NewMemFs(),
+	}
+}
+
+func (fs *FailingFs) Exists(name string) (bool, error) {
+	if fs.ExistsError != nil {
+		return false, fs.ExistsError
+	}
+	return fs.MemFs.Exists(name)
+}
+
+func (fs *FailingFs) WriteFile(name string, data []byte) error {
+	if fs.WriteError != nil {
+		return fs.WriteError
+	}
+	return fs.MemFs.WriteFile(name, data)
+}
+
+func (fs *FailingFs) ReadFile(name string) ([]byte, error) {
+	if fs.ReadError != nil {
+		return []byte{}, fs.ReadError
+	}
+	return fs.MemFs.ReadFile(name)
+}
+
+func (fs *FailingFs) List(dir string) ([]string, error) {
+	if fs.ListError != nil {
+		return nil, fs.ListError
+	}
+	return fs.MemFs.List(dir)
+}
+
+func (fs *FailingFs) Remove(name string) error {
+	if fs.RemoveError != nil {
+		return fs.RemoveError
+	}
+	return fs.MemFs.Remove(name)
+}
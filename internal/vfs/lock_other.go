@@ -0,0 +1,11 @@
+//go:build !unix
+
+package vfs
+
+/*
+lockDir is a no-op in platforms without flock. Writes are still atomic, so
+readers never get a partial snapshot.
+*/
+func lockDir(dir string) (func(), error) {
+	return func() {}, nil
+}
@@ -1,6 +1,13 @@
 package vfs
 
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
 type MemFs struct {
+	sync.RWMutex
 	files map[string][]byte
 }
 
@@ -11,6 +18,8 @@ func NewMemFs() *MemFs {
 }
 
 func (fs *MemFs) Exists(name string) (bool, error) {
+	fs.RLock()
+	defer fs.RUnlock()
 	_, ok := fs.files[name]
 	if ok {
 		return true, nil
@@ -19,14 +28,45 @@ func (fs *MemFs) Exists(name string) (bool, error) {
 }
 
 func (fs *MemFs) WriteFile(name string, data []byte) error {
+	fs.Lock()
+	defer fs.Unlock()
 	fs.files[name] = data
 	return nil
 }
 
 func (fs *MemFs) ReadFile(name string) ([]byte, error) {
+	fs.RLock()
+	defer fs.RUnlock()
 	content, ok := fs.files[name]
 	if ok {
 		return content, nil
 	}
 	return []byte{}, SnapshotNotFound
 }
+
+/*
+List returns the paths of all files under dir, including subfolders, sorted
+*/
+func (fs *MemFs) List(dir string) ([]string, error) {
+	fs.RLock()
+	defer fs.RUnlock()
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	var files []string
+	for name := range fs.files {
+		if dir == "" || dir == "." || strings.HasPrefix(name, prefix) {
+			files = append(files, name)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (fs *MemFs) Remove(name string) error {
+	fs.Lock()
+	defer fs.Unlock()
+	if _, ok := fs.files[name]; !ok {
+		return SnapshotNotFound
+	}
+	delete(fs.files, name)
+	return nil
+}
@@ -81,4 +81,36 @@ func TestMemFs(t *testing.T) {
 		assert.False(t, exists)
 	})
 
+	t.Run("should list files in folder and subfolders", func(t *testing.T) {
+		memFs := NewMemFs()
+		_ = memFs.WriteFile("list/b.snap", []byte("b"))
+		_ = memFs.WriteFile("list/a/a.snap", []byte("a"))
+		_ = memFs.WriteFile("other/c.snap", []byte("c"))
+
+		files, err := memFs.List("list")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"list/a/a.snap", "list/b.snap"}, files)
+	})
+
+	t.Run("should list nothing if folder does not exist", func(t *testing.T) {
+		files, err := memFs.List("no_folder")
+		assert.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("should remove file", func(t *testing.T) {
+		filePath := "to_remove.snap"
+		writeFile(t, filePath, []byte("some content"))
+
+		err := memFs.Remove(filePath)
+		assert.NoError(t, err)
+
+		exists, _ := memFs.Exists(filePath)
+		assert.False(t, exists)
+	})
+
+	t.Run("should return error removing a file that does not exist", func(t *testing.T) {
+		err := memFs.Remove("no_existent.snap")
+		assert.True(t, errors.Is(err, SnapshotNotFound))
+	})
 }
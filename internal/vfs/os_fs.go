@@ -2,8 +2,13 @@ package vfs
 
 import (
 	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
 )
 
 type OsFs struct {
@@ -24,6 +29,17 @@ func (o OsFs) Exists(name string) (bool, error) {
 	return true, err
 }
 
+/*
+WriteFile replaces the file atomically: data goes to a temporary file in the
+same folder that is renamed to name, so readers in this or other processes
+never see a truncated snapshot. A lock on the folder keeps writers in other
+processes from interleaving. The lock covers only the write: a process that
+reads, compares and writes a snapshot can still overwrite changes made by
+another one in the meantime.
+
+An existing file keeps its permissions. New files are created with 0666 minus
+the umask.
+*/
 func (o OsFs) WriteFile(name string, data []byte) error {
 	p := path.Dir(name)
 	_, err := os.Stat(p)
@@ -36,7 +52,62 @@ func (o OsFs) WriteFile(name string, data []byte) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(name, data, os.ModePerm)
+
+	unlock, err := lockDir(p)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return writeAtomic(name, data)
+}
+
+func writeAtomic(name string, data []byte) error {
+	tmp, err := createTemp(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(name)
+	if err == nil {
+		err = os.Chmod(tmp.Name(), info.Mode().Perm())
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+var tempCounter uint64
+
+/*
+createTemp creates a temporary file next to name. Unlike os.CreateTemp, it
+uses 0666 as permissions, so the umask applies as for any new file.
+*/
+func createTemp(name string) (*os.File, error) {
+	for {
+		n := atomic.AddUint64(&tempCounter, 1)
+		tmp := fmt.Sprintf("%s/.%s.%d.%d.tmp", path.Dir(name), path.Base(name), os.Getpid(), n)
+		f, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		return f, err
+	}
 }
 
 func (o OsFs) ReadFile(name string) ([]byte, error) {
@@ -46,3 +117,37 @@ func (o OsFs) ReadFile(name string) ([]byte, error) {
 	}
 	return content, err
 }
+
+/*
+List returns the paths of all files under dir, including subfolders, sorted.
+Paths use forward slashes, like the ones generated by golden. If dir doesn't
+exist, the list is empty.
+*/
+func (o OsFs) List(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			files = append(files, filepath.ToSlash(p))
+		}
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (o OsFs) Remove(name string) error {
+	err := os.Remove(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return SnapshotNotFound
+	}
+	return err
+}
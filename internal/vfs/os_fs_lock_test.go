@@ -0,0 +1,105 @@
+package vfs
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const (
+	helperEnv     = "GOLDEN_VFS_HELPER_WRITER"
+	helperFileEnv = "GOLDEN_VFS_HELPER_FILE"
+	payloadSize   = 256 * 1024
+	writes        = 20
+)
+
+/*
+payload generates content that is easy to validate: the same byte repeated
+*/
+func payload(writer int) []byte {
+	return bytes.Repeat([]byte{byte('a' + writer)}, payloadSize)
+}
+
+func validPayload(content []byte) bool {
+	if len(content) != payloadSize {
+		return false
+	}
+	return bytes.Count(content, content[:1]) == payloadSize
+}
+
+/*
+TestHelperWriter is not a real test. It is run as a separate process by
+TestOsFsAcrossProcesses to write the shared snapshot many times.
+*/
+func TestHelperWriter(t *testing.T) {
+	writer, err := strconv.Atoi(os.Getenv(helperEnv))
+	if err != nil {
+		t.Skip("only run as helper process")
+	}
+	osFs := NewOsFs()
+	for i := 0; i < writes; i++ {
+		err := osFs.WriteFile(os.Getenv(helperFileEnv), payload(writer))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestOsFsAcrossProcesses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("spawns processes")
+	}
+
+	dir := t.TempDir()
+	shared := filepath.ToSlash(filepath.Join(dir, "snapshots", "shared.snap"))
+
+	var helpers []*exec.Cmd
+	for i := 0; i < 4; i++ {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestHelperWriter$")
+		cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", helperEnv, i), helperFileEnv+"="+shared)
+		err := cmd.Start()
+		assert.NoError(t, err)
+		helpers = append(helpers, cmd)
+	}
+
+	done := make(chan error, len(helpers))
+	go func() {
+		for _, cmd := range helpers {
+			done <- cmd.Wait()
+		}
+		close(done)
+	}()
+
+	osFs := NewOsFs()
+	reads := 0
+	timeout := time.After(30 * time.Second)
+	for finished := 0; finished < len(helpers); {
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+			finished++
+		case <-timeout:
+			t.Fatal("helper processes didn't finish")
+		default:
+			content, err := osFs.ReadFile(shared)
+			if err == nil {
+				reads++
+				assert.Truef(t, validPayload(content), "read a partial snapshot of %d bytes", len(content))
+			}
+		}
+	}
+
+	content, err := osFs.ReadFile(shared)
+	assert.NoError(t, err)
+	assert.True(t, validPayload(content))
+
+	files, err := osFs.List(filepath.Join(dir, "snapshots"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{shared}, files, "temporary files were left behind")
+}
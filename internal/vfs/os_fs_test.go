@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -39,6 +40,31 @@ func TestOsFs(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("should create files with default permissions", func(t *testing.T) {
+		dir := filepath.ToSlash(t.TempDir())
+		reference := dir + "/reference.snap"
+		err := os.WriteFile(reference, []byte("reference"), 0666)
+		assert.NoError(t, err)
+
+		err = osFs.WriteFile(dir+"/new.snap", []byte("some content"))
+		assert.NoError(t, err)
+
+		assert.Equal(t, fileMode(t, reference), fileMode(t, dir+"/new.snap"))
+	})
+
+	t.Run("should keep permissions of existing file", func(t *testing.T) {
+		filePath := filepath.ToSlash(t.TempDir()) + "/existing.snap"
+		err := os.WriteFile(filePath, []byte("original content"), 0600)
+		assert.NoError(t, err)
+		err = os.Chmod(filePath, 0640)
+		assert.NoError(t, err)
+
+		err = osFs.WriteFile(filePath, []byte("new content"))
+		assert.NoError(t, err)
+
+		assert.Equal(t, os.FileMode(0640), fileMode(t, filePath))
+	})
+
 	t.Run("should allow full paths", func(t *testing.T) {
 		filePath := "testdata/file.snap"
 
@@ -92,4 +118,47 @@ func TestOsFs(t *testing.T) {
 		assert.NoError(t, err)
 		assert.False(t, exists)
 	})
+
+	t.Run("should list files in folder and subfolders", func(t *testing.T) {
+		dir := filepath.ToSlash(t.TempDir())
+		err := osFs.WriteFile(dir+"/b.snap", []byte("b"))
+		assert.NoError(t, err)
+		err = osFs.WriteFile(dir+"/a/a.snap", []byte("a"))
+		assert.NoError(t, err)
+
+		files, err := osFs.List(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{dir + "/a/a.snap", dir + "/b.snap"}, files)
+	})
+
+	t.Run("should list nothing if folder does not exist", func(t *testing.T) {
+		files, err := osFs.List("no_folder")
+		assert.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("should remove file", func(t *testing.T) {
+		filePath := "to_remove.snap"
+
+		err := osFs.WriteFile(filePath, []byte("some content"))
+		assert.NoError(t, err)
+
+		err = osFs.Remove(filePath)
+		assert.NoError(t, err)
+
+		exists, err := osFs.Exists(filePath)
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("should return error removing a file that does not exist", func(t *testing.T) {
+		err := osFs.Remove("no_existent.snap")
+		assert.True(t, errors.Is(err, SnapshotNotFound))
+	})
+}
+
+func fileMode(t *testing.T, name string) os.FileMode {
+	info, err := os.Stat(name)
+	assert.NoError(t, err)
+	return info.Mode().Perm()
 }
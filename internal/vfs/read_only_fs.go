@@ -0,0 +1,41 @@
+package vfs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ReadOnly = errors.New("read-only mode")
+
+/*
+ReadOnlyFs wraps a filesystem to make sure that nothing changes. Reading
+operations are delegated, but writing operations fail with an error that
+describes what would have been done.
+*/
+type ReadOnlyFs struct {
+	fs Vfs
+}
+
+func NewReadOnlyFs(fs Vfs) ReadOnlyFs {
+	return ReadOnlyFs{fs: fs}
+}
+
+func (r ReadOnlyFs) Exists(name string) (bool, error) {
+	return r.fs.Exists(name)
+}
+
+func (r ReadOnlyFs) WriteFile(name string, data []byte) error {
+	return fmt.Errorf("%w: would have written %d bytes to %s:\n%s", ReadOnly, len(data), name, data)
+}
+
+func (r ReadOnlyFs) ReadFile(name string) ([]byte, error) {
+	return r.fs.ReadFile(name)
+}
+
+func (r ReadOnlyFs) List(dir string) ([]string, error) {
+	return r.fs.List(dir)
+}
+
+func (r ReadOnlyFs) Remove(name string) error {
+	return fmt.Errorf("%w: would have removed %s", ReadOnly, name)
+}
@@ -0,0 +1,42 @@
+package vfs
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReadOnlyFs(t *testing.T) {
+	memFs := NewMemFs()
+	_ = memFs.WriteFile("testdata/existing.snap", []byte("existing content"))
+	readOnly := NewReadOnlyFs(memFs)
+
+	t.Run("should read files", func(t *testing.T) {
+		content, err := readOnly.ReadFile("testdata/existing.snap")
+		assert.NoError(t, err)
+		assert.Equal(t, "existing content", string(content))
+
+		exists, err := readOnly.Exists("testdata/existing.snap")
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		files, err := readOnly.List("testdata")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"testdata/existing.snap"}, files)
+	})
+
+	t.Run("should refuse to write files", func(t *testing.T) {
+		err := readOnly.WriteFile("testdata/existing.snap", []byte("new content"))
+		assert.True(t, errors.Is(err, ReadOnly))
+		assert.Contains(t, err.Error(), "would have written 11 bytes to testdata/existing.snap:\nnew content")
+
+		AssertContentWasStored(t, memFs, "testdata/existing.snap", []byte("existing content"))
+	})
+
+	t.Run("should refuse to remove files", func(t *testing.T) {
+		err := readOnly.Remove("testdata/existing.snap")
+		assert.True(t, errors.Is(err, ReadOnly))
+
+		AssertSnapshotWasCreated(t, memFs, "testdata/existing.snap")
+	})
+}
@@ -6,6 +6,8 @@ type Vfs interface {
 	Exists(name string) (bool, error)
 	WriteFile(name string, data []byte) error
 	ReadFile(name string) ([]byte, error)
+	List(dir string) ([]string, error)
+	Remove(name string) error
 }
 
 var SnapshotNotFound = errors.New("snapshot not found")
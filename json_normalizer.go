@@ -20,6 +20,14 @@ type JsonNormalizer struct {
 const indent = "  "
 const prefix = ""
 
+/*
+Format identifies the format of the snapshots. It can be used in path templates
+with the {format} placeholder
+*/
+func (n JsonNormalizer) Format() string {
+	return "json"
+}
+
 func (n JsonNormalizer) Normalize(subject any) (string, error) {
 	var output string
 	if _, ok := subject.(string); ok {
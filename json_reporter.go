@@ -0,0 +1,147 @@
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+JSONDiffReporter compares snapshot and subject as JSON documents and reports
+the values that were added (+), removed (-) or changed (~) by their JSON path:
+
+	~ $.items[3].price: 10.5 -> 11.0
+	+ $.items[4]: {"name":"Pen","price":2}
+	- $.customer.phone: "555"
+
+It uses a line diff if any of them is not valid JSON.
+*/
+type JSONDiffReporter struct{}
+
+func NewJSONDiffReporter() JSONDiffReporter {
+	return JSONDiffReporter{}
+}
+
+func (JSONDiffReporter) Differences(want, got string) string {
+	if want == got {
+		return noDifferences
+	}
+	wantValue, err := decodeJSON(want)
+	if err != nil {
+		return LineDiffReporter{}.Differences(want, got)
+	}
+	gotValue, err := decodeJSON(got)
+	if err != nil {
+		return LineDiffReporter{}.Differences(want, got)
+	}
+
+	var changes []string
+	compareJSON("$", wantValue, gotValue, &changes)
+	if len(changes) == 0 {
+		return LineDiffReporter{}.Differences(want, got)
+	}
+	return fmt.Sprintf(diffHeaderFormat, strings.Join(changes, "\n"))
+}
+
+func decodeJSON(text string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var value any
+	err := decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected content after JSON value")
+	}
+	return value, nil
+}
+
+func compareJSON(path string, want, got any, changes *[]string) {
+	switch w := want.(type) {
+	case map[string]any:
+		if g, ok := got.(map[string]any); ok {
+			compareObjects(path, w, g, changes)
+			return
+		}
+	case []any:
+		if g, ok := got.([]any); ok {
+			compareArrays(path, w, g, changes)
+			return
+		}
+	}
+	wantJSON, gotJSON := encodeJSON(want), encodeJSON(got)
+	if wantJSON != gotJSON {
+		*changes = append(*changes, fmt.Sprintf("~ %s: %s -> %s", path, wantJSON, gotJSON))
+	}
+}
+
+func compareObjects(path string, want, got map[string]any, changes *[]string) {
+	keys := make(map[string]bool)
+	for key := range want {
+		keys[key] = true
+	}
+	for key := range got {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		w, inWant := want[key]
+		g, inGot := got[key]
+		p := path + jsonKey(key)
+		switch {
+		case !inGot:
+			*changes = append(*changes, fmt.Sprintf("- %s: %s", p, encodeJSON(w)))
+		case !inWant:
+			*changes = append(*changes, fmt.Sprintf("+ %s: %s", p, encodeJSON(g)))
+		default:
+			compareJSON(p, w, g, changes)
+		}
+	}
+}
+
+func compareArrays(path string, want, got []any, changes *[]string) {
+	for i := 0; i < len(want) || i < len(got); i++ {
+		p := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case i >= len(got):
+			*changes = append(*changes, fmt.Sprintf("- %s: %s", p, encodeJSON(want[i])))
+		case i >= len(want):
+			*changes = append(*changes, fmt.Sprintf("+ %s: %s", p, encodeJSON(got[i])))
+		default:
+			compareJSON(p, want[i], got[i], changes)
+		}
+	}
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func jsonKey(key string) string {
+	if identifier.MatchString(key) {
+		return "." + key
+	}
+	return "[" + strconv.Quote(key) + "]"
+}
+
+/*
+encodeJSON shows a value as compact JSON
+*/
+func encodeJSON(value any) string {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,108 @@
+package golden
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+SnapshotLayout decides the path of the snapshot inside the snapshots folder,
+without extension, from the package and the full name of the test, as returned
+by t.Name()
+*/
+type SnapshotLayout interface {
+	Path(pkg, test string) string
+}
+
+/*
+NestedLayout is the default layout. It uses the name of the test as is, so
+subtests are stored in a folder for each parent test:
+
+	TestSomething/should_do_something
+*/
+type NestedLayout struct{}
+
+func (NestedLayout) Path(_, test string) string {
+	return test
+}
+
+/*
+FlatLayout stores all the snapshots in the same folder, joining the names of
+subtests with a double underscore. Characters unsafe in file names are escaped.
+
+	TestSomething__should_do_something
+*/
+type FlatLayout struct{}
+
+func (FlatLayout) Path(_, test string) string {
+	return strings.Join(sanitizeAll(strings.Split(test, "/")), "__")
+}
+
+/*
+TopLevelLayout stores the snapshots of subtests in a folder for each top level
+test, joining the names of nested subtests with a double underscore.
+Characters unsafe in file names are escaped.
+
+	TestSomething/should_do_something__in_some_case
+*/
+type TopLevelLayout struct{}
+
+func (TopLevelLayout) Path(_, test string) string {
+	segments := sanitizeAll(strings.Split(test, "/"))
+	if len(segments) == 1 {
+		return segments[0]
+	}
+	return segments[0] + "/" + strings.Join(segments[1:], "__")
+}
+
+/*
+PackageLayout stores the snapshots in a folder for each package, like
+FlatLayout does inside it. It is useful if several packages share the same
+snapshots folder.
+
+	mypackage/TestSomething__should_do_something
+*/
+type PackageLayout struct{}
+
+func (PackageLayout) Path(pkg, test string) string {
+	return sanitize(pkg) + "/" + FlatLayout{}.Path(pkg, test)
+}
+
+func sanitizeAll(segments []string) []string {
+	sanitized := make([]string, len(segments))
+	for i, segment := range segments {
+		sanitized[i] = sanitize(segment)
+	}
+	return sanitized
+}
+
+/*
+sanitize escapes every byte that is not an ASCII letter, digit, dash,
+underscore or dot as %XX, so names are safe in any filesystem and the same
+test always gets the same file name. Names made only of dots are escaped too.
+*/
+func sanitize(segment string) string {
+	if strings.Trim(segment, ".") == "" {
+		return strings.ReplaceAll(segment, ".", "%2E")
+	}
+	var b strings.Builder
+	for i := 0; i < len(segment); i++ {
+		c := segment[i]
+		if safeInFileName(c) {
+			b.WriteByte(c)
+			continue
+		}
+		_, _ = fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func safeInFileName(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	case c == '-' || c == '_' || c == '.':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package golden_test
+
+import (
+	"github.com/franiglesias/golden"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNestedLayout(t *testing.T) {
+	layout := golden.NestedLayout{}
+
+	t.Run("should use test name as is", func(t *testing.T) {
+		assert.Equal(t, "TestSomething/should_do:something", layout.Path("pkg", "TestSomething/should_do:something"))
+	})
+}
+
+func TestFlatLayout(t *testing.T) {
+	layout := golden.FlatLayout{}
+
+	t.Run("should use top level test name", func(t *testing.T) {
+		assert.Equal(t, "TestSomething", layout.Path("pkg", "TestSomething"))
+	})
+
+	t.Run("should join subtests", func(t *testing.T) {
+		assert.Equal(t, "TestSomething__should_do__in_case", layout.Path("pkg", "TestSomething/should_do/in_case"))
+	})
+
+	t.Run("should escape unsafe characters", func(t *testing.T) {
+		assert.Equal(t, "TestSomething__a%3Ab%2Ac%3F%22%3C%3E%7C%5C", layout.Path("pkg", `TestSomething/a:b*c?"<>|\`))
+	})
+
+	t.Run("should escape non ascii characters", func(t *testing.T) {
+		assert.Equal(t, "TestSomething__ni%C3%B1o", layout.Path("pkg", "TestSomething/niño"))
+	})
+
+	t.Run("should escape names made of dots", func(t *testing.T) {
+		assert.Equal(t, "TestSomething__%2E%2E", layout.Path("pkg", "TestSomething/.."))
+	})
+
+	t.Run("should keep names of repeated subtests", func(t *testing.T) {
+		assert.Equal(t, "TestSomething__case%2301", layout.Path("pkg", "TestSomething/case#01"))
+	})
+}
+
+func TestTopLevelLayout(t *testing.T) {
+	layout := golden.TopLevelLayout{}
+
+	t.Run("should use top level test name", func(t *testing.T) {
+		assert.Equal(t, "TestSomething", layout.Path("pkg", "TestSomething"))
+	})
+
+	t.Run("should use a folder for top level test", func(t *testing.T) {
+		assert.Equal(t, "TestSomething/should_do__in_case", layout.Path("pkg", "TestSomething/should_do/in_case"))
+	})
+
+	t.Run("should escape unsafe characters", func(t *testing.T) {
+		assert.Equal(t, "TestSomething/a%3Ab", layout.Path("pkg", "TestSomething/a:b"))
+	})
+}
+
+func TestPackageLayout(t *testing.T) {
+	layout := golden.PackageLayout{}
+
+	t.Run("should use a folder for the package", func(t *testing.T) {
+		assert.Equal(t, "pkg/TestSomething__should_do", layout.Path("pkg", "TestSomething/should_do"))
+	})
+}
@@ -0,0 +1,36 @@
+package golden
+
+import "sync"
+
+/*
+snapshotLocks provides a lock for each snapshot path, so tests verifying
+different snapshots can run in parallel, while tests targeting the same
+snapshot wait for each other
+*/
+type snapshotLocks struct {
+	sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func newSnapshotLocks() *snapshotLocks {
+	return &snapshotLocks{
+		locks: make(map[string]*sync.Mutex),
+	}
+}
+
+/*
+lock blocks until the snapshot is available and returns the function to
+release it
+*/
+func (l *snapshotLocks) lock(name string) func() {
+	l.Lock()
+	m, ok := l.locks[name]
+	if !ok {
+		m = &sync.Mutex{}
+		l.locks[name] = m
+	}
+	l.Unlock()
+
+	m.Lock()
+	return m.Unlock
+}
@@ -0,0 +1,263 @@
+package golden
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+/*
+Modes that can be activated for the whole test run, without touching the code
+of the tests, using environment variables or test flags.
+*/
+
+const updateEnv = "GOLDEN_UPDATE"
+
+var updateFlag = flag.Bool("golden.update", false, "golden: rewrite every snapshot with the current subject")
+
+/*
+updateRequested returns true if update mode was activated for the run with
+
+	GOLDEN_UPDATE=1 go test ./...
+
+or
+
+	go test ./... -golden.update
+*/
+func updateRequested() bool {
+	return *updateFlag || envEnabled(updateEnv)
+}
+
+const strictEnv = "GOLDEN_STRICT"
+const ciEnv = "CI"
+
+var strictFlag = flag.Bool("golden.strict", false, "golden: fail if a snapshot doesn't exist instead of creating it")
+
+/*
+strictRequested returns true if the run must not create or change snapshots.
+It is activated by GOLDEN_STRICT=1 or -golden.strict.
+*/
+func strictRequested() bool {
+	return *strictFlag || envEnabled(strictEnv)
+}
+
+/*
+ciRequested returns true if the run happens in a CI service, that usually
+defines CI=true
+*/
+func ciRequested() bool {
+	return envEnabled(ciEnv)
+}
+
+const readOnlyEnv = "GOLDEN_READONLY"
+
+var readOnlyFlag = flag.Bool("golden.readonly", false, "golden: fail if any snapshot would be written")
+
+/*
+readOnlyRequested returns true if the run must not write anything, for example
+when running against a read-only checkout
+*/
+func readOnlyRequested() bool {
+	return *readOnlyFlag || envEnabled(readOnlyEnv)
+}
+
+const approveEnv = "GOLDEN_APPROVE"
+
+var approveFlag = flag.String("golden.approve", "", "golden: put tests matching the `regexp` in approval mode")
+
+/*
+approvalRequested returns true if the test was selected to run in approval mode
+with a pattern, without adding WaitApproval to the code:
+
+	GOLDEN_APPROVE='TestInvoice/(pdf|html)' go test ./...
+
+or
+
+	go test ./... -golden.approve 'TestInvoice/(pdf|html)'
+
+The pattern works like the one of -run: it is split by slashes that are not
+inside brackets or parentheses, and each part is a regular expression matched
+against the name of the test or subtest at the same level. Subtests of a
+matching test are selected too. A bar outside brackets or parentheses separates
+alternative patterns.
+*/
+func approvalRequested(test string) (bool, error) {
+	pattern := *approveFlag
+	if pattern == "" {
+		pattern = os.Getenv(approveEnv)
+	}
+	if pattern == "" {
+		return false, nil
+	}
+	return matchTestName(pattern, test)
+}
+
+/*
+testNamePattern holds the alternatives of a pattern like the one of -run, each
+one with a regular expression for every level of the test name
+*/
+type testNamePattern [][]*regexp.Regexp
+
+type compiledPattern struct {
+	pattern testNamePattern
+	err     error
+}
+
+/*
+testNamePatterns caches the compiled patterns, because they are checked on
+every verification
+*/
+var testNamePatterns sync.Map
+
+func matchTestName(pattern string, test string) (bool, error) {
+	compiled, ok := testNamePatterns.Load(pattern)
+	if !ok {
+		p, err := compileTestNamePattern(pattern)
+		compiled, _ = testNamePatterns.LoadOrStore(pattern, compiledPattern{pattern: p, err: err})
+	}
+	c := compiled.(compiledPattern)
+	if c.err != nil {
+		return false, c.err
+	}
+	return c.pattern.matches(test), nil
+}
+
+func compileTestNamePattern(pattern string) (testNamePattern, error) {
+	var compiled testNamePattern
+	for _, alternative := range splitTestNamePattern(pattern) {
+		var levels []*regexp.Regexp
+		for _, part := range alternative {
+			re, err := regexp.Compile(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid approval pattern %s: %w", pattern, err)
+			}
+			levels = append(levels, re)
+		}
+		compiled = append(compiled, levels)
+	}
+	return compiled, nil
+}
+
+func (p testNamePattern) matches(test string) bool {
+	names := strings.Split(test, "/")
+	for _, levels := range p {
+		if matchLevels(levels, names) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchLevels(levels []*regexp.Regexp, names []string) bool {
+	if len(levels) > len(names) {
+		return false
+	}
+	for i, re := range levels {
+		if !re.MatchString(names[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+splitTestNamePattern splits the pattern in alternatives and levels, as the
+testing package does with -run: slashes and bars inside brackets or
+parentheses, or escaped, belong to the regular expression.
+*/
+func splitTestNamePattern(pattern string) [][]string {
+	var alternatives [][]string
+	var levels []string
+	brackets, parens := 0, 0
+	for i := 0; i < len(pattern); {
+		switch pattern[i] {
+		case '[':
+			brackets++
+		case ']':
+			if brackets > 0 {
+				brackets--
+			}
+		case '(':
+			if brackets == 0 {
+				parens++
+			}
+		case ')':
+			if brackets == 0 {
+				parens--
+			}
+		case '\\':
+			i++
+		case '/', '|':
+			if brackets == 0 && parens == 0 {
+				levels = append(levels, pattern[:i])
+				if pattern[i] == '|' {
+					alternatives = append(alternatives, levels)
+					levels = nil
+				}
+				pattern = pattern[i+1:]
+				i = 0
+				continue
+			}
+		}
+		i++
+	}
+	levels = append(levels, pattern)
+	return append(alternatives, levels)
+}
+
+const htmlReportEnv = "GOLDEN_HTML_REPORT"
+
+var htmlReportFlag = flag.String("golden.html", "", "golden: write an HTML report of the failed verifications to `file` with golden.Main")
+
+/*
+htmlReportPath returns the file where the HTML report of the run should be
+written, or an empty string if it was not requested
+*/
+func htmlReportPath() string {
+	if *htmlReportFlag != "" {
+		return *htmlReportFlag
+	}
+	return os.Getenv(htmlReportEnv)
+}
+
+const jsonReportEnv = "GOLDEN_JSON_REPORT"
+
+var jsonReportFlag = flag.String("golden.json", "", "golden: write a JSON Lines report of the verifications to `file` with golden.Main")
+
+/*
+jsonReportPath returns the file where the JSON Lines report of the run should
+be written, or an empty string if it was not requested
+*/
+func jsonReportPath() string {
+	if *jsonReportFlag != "" {
+		return *jsonReportFlag
+	}
+	return os.Getenv(jsonReportEnv)
+}
+
+const pruneEnv = "GOLDEN_PRUNE"
+
+var pruneFlag = flag.Bool("golden.prune", false, "golden: remove obsolete snapshots after running the tests with golden.Main")
+
+/*
+pruneRequested returns true if obsolete snapshots should be removed by Main
+*/
+func pruneRequested() bool {
+	return *pruneFlag || envEnabled(pruneEnv)
+}
+
+/*
+envEnabled returns true if the environment variable contains a value that can
+be interpreted as true (1, t, true, TRUE...)
+*/
+func envEnabled(key string) bool {
+	enabled, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return false
+	}
+	return enabled
+}
@@ -0,0 +1,51 @@
+package golden
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMatchTestName(t *testing.T) {
+	examples := []struct {
+		pattern string
+		test    string
+		match   bool
+	}{
+		{"TestInvoice", "TestInvoice", true},
+		{"TestInvoice", "TestInvoiceTotal", true},
+		{"^TestInvoice$", "TestInvoiceTotal", false},
+		{"TestInvoice", "TestInvoice/pdf", true},
+		{"TestInvoice.*/pdf", "TestInvoiceTotal/pdf", true},
+		{"TestInvoice.*/pdf", "TestInvoiceTotal/html", false},
+		{"TestInvoice/pdf", "TestInvoice", false},
+		{"/pdf", "TestInvoice/pdf", true},
+		{"Invoice/pdf", "TestCustomer/pdf", false},
+		{"TestInvoice/[a/b]", "TestInvoice/a", true},
+		{"TestInvoice/(pdf|html)", "TestInvoice/html", true},
+		{"TestInvoice/(pdf|html)", "TestCustomer/html", false},
+		{"TestInvoice/(pdf/v1|html)", "TestInvoice/html", true},
+		{"TestInvoice/pdf|TestCustomer", "TestCustomer/html", true},
+		{"TestInvoice/pdf|TestCustomer", "TestInvoice/html", false},
+		{`TestInvoice\/pdf`, "TestInvoice", false},
+	}
+	for _, example := range examples {
+		match, err := matchTestName(example.pattern, example.test)
+		assert.NoError(t, err)
+		assert.Equalf(t, example.match, match, "%s should match %s: %t", example.pattern, example.test, example.match)
+	}
+}
+
+func TestSplitTestNamePattern(t *testing.T) {
+	assert.Equal(t, [][]string{{"TestInvoice", "pdf"}}, splitTestNamePattern("TestInvoice/pdf"))
+	assert.Equal(t, [][]string{{"TestInvoice", "[a/b]"}}, splitTestNamePattern("TestInvoice/[a/b]"))
+	assert.Equal(t, [][]string{{"TestInvoice", "(pdf/v1|html)"}}, splitTestNamePattern("TestInvoice/(pdf/v1|html)"))
+	assert.Equal(t, [][]string{{"TestA", "x"}, {"TestB"}}, splitTestNamePattern("TestA/x|TestB"))
+}
+
+func TestMatchTestNameErrors(t *testing.T) {
+	_, err := matchTestName("TestInvoice/(", "TestInvoice/pdf")
+	assert.ErrorContains(t, err, "invalid approval pattern TestInvoice/(")
+
+	_, err = matchTestName("TestInvoice/(", "TestInvoice/pdf")
+	assert.Error(t, err, "errors are cached too")
+}
@@ -0,0 +1,51 @@
+package golden
+
+import "strings"
+
+/*
+MultiReporter combines the output of several reporters. For example, a compact
+line diff for the test output and a diff tool:
+
+	golden.NewMultiReporter(golden.NewLineDiffReporter(), golden.NewDiffToolReporter(nil))
+
+Every reporter receives the context of the snapshot, if it accepts it.
+*/
+type MultiReporter struct {
+	reporters []DiffReporter
+}
+
+/*
+NewMultiReporter combines the reporters, in order. Reporters that are
+MultiReporter are flattened and nil reporters are ignored.
+*/
+func NewMultiReporter(reporters ...DiffReporter) MultiReporter {
+	var combined []DiffReporter
+	for _, reporter := range reporters {
+		switch r := reporter.(type) {
+		case nil:
+			continue
+		case MultiReporter:
+			combined = append(combined, r.reporters...)
+		default:
+			combined = append(combined, r)
+		}
+	}
+	return MultiReporter{
+		reporters: combined,
+	}
+}
+
+func (m MultiReporter) Differences(want, got string) string {
+	return m.ContextDifferences(ReportContext{}, want, got)
+}
+
+func (m MultiReporter) ContextDifferences(ctx ReportContext, want, got string) string {
+	if want == got || len(m.reporters) == 0 {
+		return noDifferences
+	}
+	reports := make([]string, len(m.reporters))
+	for i, reporter := range m.reporters {
+		reports[i] = reportDifferences(reporter, ctx, want, got)
+	}
+	return strings.Join(reports, "\n")
+}
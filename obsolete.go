@@ -0,0 +1,401 @@
+package golden
+
+import (
+	"flag"
+	"fmt"
+	"github.com/franiglesias/golden/internal/vfs"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/*
+usage records the snapshots touched during a test run, and the folders,
+extensions and path templates they use, so we can find snapshot files that no
+test reads anymore. Roots are the paths of the snapshots of the top level
+tests, without extension, that the snapshots of their subtests start with.
+*/
+type usage struct {
+	sync.Mutex
+	fs        any
+	snapshots map[string]bool
+	folders   map[string]bool
+	exts      map[string]bool
+	templates map[string]*regexp.Regexp
+	roots     map[string]bool
+	skipped   bool
+}
+
+func newUsage(fs vfs.Vfs) *usage {
+	return &usage{
+		fs:        fsKey(fs),
+		snapshots: make(map[string]bool),
+		folders:   make(map[string]bool),
+		exts:      make(map[string]bool),
+		templates: make(map[string]*regexp.Regexp),
+		roots:     make(map[string]bool),
+	}
+}
+
+func (u *usage) record(t Failable, name string, conf Config) {
+	u.Lock()
+	defer u.Unlock()
+	u.snapshots[name] = true
+	u.folders[conf.folder] = true
+	if conf.name == "" {
+		u.roots[testRoot(t, conf)] = true
+	}
+	if conf.name == "" && conf.template != "" {
+		pattern := conf.templatePattern()
+		if _, ok := u.templates[pattern]; !ok {
+			u.templates[pattern] = regexp.MustCompile(pattern)
+		}
+	} else {
+		u.exts[conf.ext] = true
+	}
+	recorders.add(u)
+}
+
+type skippable interface {
+	Cleanup(f func())
+	Skipped() bool
+}
+
+/*
+watch takes note if the test is skipped after verifying. Its remaining
+snapshots would be taken as obsolete.
+*/
+func (u *usage) watch(t Failable) {
+	s, ok := t.(skippable)
+	if !ok {
+		return
+	}
+	s.Cleanup(func() {
+		if s.Skipped() {
+			u.Lock()
+			defer u.Unlock()
+			u.skipped = true
+		}
+	})
+}
+
+func (u *usage) used(name string) bool {
+	u.Lock()
+	defer u.Unlock()
+	return u.snapshots[name]
+}
+
+func (u *usage) isSnapshot(name string) bool {
+	u.Lock()
+	defer u.Unlock()
+	if isReceived(name) {
+		return false
+	}
+	for ext := range u.exts {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	for _, template := range u.templates {
+		if template.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+var rootRest = regexp.MustCompile(`^(_[0-9]+)?(\.|/|__|$)`)
+
+/*
+rooted returns true if the file may be a snapshot of one of the top level tests
+that verified snapshots in this run
+*/
+func (u *usage) rooted(name string) bool {
+	u.Lock()
+	defer u.Unlock()
+	for root := range u.roots {
+		if strings.HasPrefix(name, root) && rootRest.MatchString(name[len(root):]) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+testRoot returns the path of the snapshot of the top level test of t, without
+extension
+*/
+func testRoot(t Failable, conf Config) string {
+	conf.index = 0
+	top := topLevelTest{name: strings.SplitN(t.Name(), "/", 2)[0]}
+	return strings.TrimSuffix(conf.snapshotPath(top), conf.ext)
+}
+
+/*
+topLevelTest stands for the top level test of a subtest to resolve its
+snapshot path
+*/
+type topLevelTest struct {
+	name string
+}
+
+func (t topLevelTest) Errorf(string, ...any) {}
+
+func (t topLevelTest) Helper() {}
+
+func (t topLevelTest) Name() string {
+	return t.name
+}
+
+func (u *usage) anySkipped() bool {
+	u.Lock()
+	defer u.Unlock()
+	return u.skipped
+}
+
+func (u *usage) snapshotFolders() []string {
+	u.Lock()
+	defer u.Unlock()
+	folders := make([]string, 0, len(u.folders))
+	for folder := range u.folders {
+		folders = append(folders, folder)
+	}
+	sort.Strings(folders)
+	return folders
+}
+
+/*
+outsideFolder returns the first folder used in the run that is outside the
+folder of the package, or an empty string
+*/
+func (u *usage) outsideFolder() string {
+	for _, folder := range u.snapshotFolders() {
+		if outsidePackage(folder) {
+			return folder
+		}
+	}
+	return ""
+}
+
+/*
+outsidePackage returns true if the folder is not inside the folder of the
+package, where go test runs its tests. Other packages may store their snapshots
+in it.
+*/
+func outsidePackage(folder string) bool {
+	if filepath.IsAbs(folder) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return true
+		}
+		folder, err = filepath.Rel(wd, folder)
+		if err != nil {
+			return true
+		}
+	}
+	folder = path.Clean(filepath.ToSlash(folder))
+	return folder == ".." || strings.HasPrefix(folder, "../")
+}
+
+/*
+recorders keeps the usages of the Golden instances that verified snapshots in
+this run, by filesystem. When several instances share a filesystem, each of
+them takes the snapshots of the others as obsolete.
+*/
+var recorders = &usageRecorders{byFs: make(map[any]map[*usage]bool)}
+
+type usageRecorders struct {
+	sync.Mutex
+	byFs map[any]map[*usage]bool
+}
+
+func (r *usageRecorders) add(u *usage) {
+	r.Lock()
+	defer r.Unlock()
+	if r.byFs[u.fs] == nil {
+		r.byFs[u.fs] = make(map[*usage]bool)
+	}
+	r.byFs[u.fs][u] = true
+}
+
+func (r *usageRecorders) shared(u *usage) bool {
+	r.Lock()
+	defer r.Unlock()
+	return len(r.byFs[u.fs]) > 1
+}
+
+/*
+fsKey identifies the filesystem, so instances created with the same one share
+the key. Filesystems that can't be compared get a key of their own.
+*/
+func fsKey(fs vfs.Vfs) any {
+	if fs == nil || !reflect.TypeOf(fs).Comparable() {
+		return new(int)
+	}
+	return fs
+}
+
+/*
+Obsolete returns the snapshot files that were not used by any Verify or Master
+call in this run. Only folders used in the run are inspected, looking for files
+with the extensions used or matching the path templates used. Received files of
+failed verifications are not considered snapshots.
+
+Folders outside the package, like ../shared, may hold the snapshots of other
+packages. In them, only files named after the top level tests that verified
+snapshots in this run are considered, so snapshots of deleted tests are not
+found there.
+
+It only makes sense after running all the tests of the package, so you will
+usually invoke it through Main.
+*/
+func (g *Golden) Obsolete() ([]string, error) {
+	var obsolete []string
+	seen := make(map[string]bool)
+	for _, folder := range g.usage.snapshotFolders() {
+		outside := outsidePackage(folder)
+		files, err := g.fs.List(folder)
+		if err != nil {
+			return nil, fmt.Errorf("could not list snapshots in %s: %w", folder, err)
+		}
+		for _, file := range files {
+			if seen[file] || !g.usage.isSnapshot(file) || g.usage.used(file) {
+				continue
+			}
+			if outside && !g.usage.rooted(file) {
+				continue
+			}
+			seen[file] = true
+			obsolete = append(obsolete, file)
+		}
+	}
+	sort.Strings(obsolete)
+	return obsolete, nil
+}
+
+/*
+Prune removes the snapshot files reported by Obsolete and returns their paths.
+
+Snapshots of tests that didn't run look obsolete, so Prune refuses to remove
+anything if a test was skipped after verifying, tests run with -test.short,
+another Golden instance verified snapshots in the same filesystem, or a
+snapshots folder is outside the package, where other packages may share it. Tests
+skipped before verifying, or excluded by build tags, can't be detected: don't
+prune in those runs, or their snapshots will be lost.
+*/
+func (g *Golden) Prune() ([]string, error) {
+	if refusal := g.pruneRefusal(); refusal != "" {
+		return nil, fmt.Errorf("obsolete snapshots not removed: %s", refusal)
+	}
+	obsolete, err := g.Obsolete()
+	if err != nil {
+		return nil, err
+	}
+	fs := g.session(g.global).fs
+	for _, file := range obsolete {
+		err = fs.Remove(file)
+		if err != nil {
+			return nil, fmt.Errorf("could not remove snapshot %s: %w", file, err)
+		}
+	}
+	return obsolete, nil
+}
+
+/*
+Runner is the part of *testing.M that Main needs
+*/
+type Runner interface {
+	Run() int
+}
+
+/*
+Main runs the tests and, if all of them pass, reports the obsolete snapshots.
+When the run is in prune mode (GOLDEN_PRUNE=1 or -golden.prune), obsolete
+snapshots are deleted. Returns the exit code of the tests.
+
+Nothing is reported if only part of the tests were run (-run or -skip flags),
+because snapshots of the tests not run would be taken as obsolete. See Prune
+for the runs in which snapshots are never removed.
+
+If requested with GOLDEN_HTML_REPORT or -golden.html, an HTML report of the
+failed verifications is written after the tests run. A JSON Lines report of all
+the verifications can be requested with GOLDEN_JSON_REPORT or -golden.json.
+
+In update mode, it prints the snapshots that were created or updated.
+*/
+func (g *Golden) Main(m Runner) int {
+	code := m.Run()
+	g.reportUpdates(os.Stdout)
+	g.writeReports(os.Stdout)
+	if code != 0 || partialRun() {
+		return code
+	}
+	g.reportObsolete(os.Stdout)
+	return code
+}
+
+func (g *Golden) reportObsolete(w io.Writer) {
+	if pruneRequested() {
+		removed, err := g.Prune()
+		if err != nil {
+			_, _ = fmt.Fprintf(w, "golden: %s\n", err)
+		}
+		for _, file := range removed {
+			_, _ = fmt.Fprintf(w, "golden: removed obsolete snapshot %s\n", file)
+		}
+		return
+	}
+
+	obsolete, err := g.Obsolete()
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "golden: %s\n", err)
+		return
+	}
+	if len(obsolete) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "golden: %d obsolete snapshots found:\n", len(obsolete))
+	for _, file := range obsolete {
+		_, _ = fmt.Fprintf(w, "  %s\n", file)
+	}
+	_, _ = fmt.Fprintf(w, "golden: run with %s=1 or -golden.prune to remove them\n", pruneEnv)
+}
+
+/*
+pruneRefusal explains why obsolete snapshots can't be removed safely in this
+run, or returns an empty string
+*/
+func (g *Golden) pruneRefusal() string {
+	switch {
+	case g.usage.anySkipped():
+		return "some tests were skipped"
+	case flagValue("test.short") == "true":
+		return "tests run with -test.short"
+	case recorders.shared(g.usage):
+		return "other Golden instances verified snapshots in the same filesystem"
+	case g.usage.outsideFolder() != "":
+		return fmt.Sprintf("snapshots folder %s is outside the package", g.usage.outsideFolder())
+	}
+	return ""
+}
+
+/*
+partialRun returns true if the test binary was asked to run only some tests
+*/
+func partialRun() bool {
+	return flagValue("test.run") != "" || flagValue("test.skip") != ""
+}
+
+func flagValue(name string) string {
+	f := flag.Lookup(name)
+	if f == nil {
+		return ""
+	}
+	return f.Value.String()
+}
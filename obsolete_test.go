@@ -0,0 +1,128 @@
+package golden
+
+import (
+	"bytes"
+	"flag"
+	"github.com/franiglesias/golden/internal/helper"
+	"github.com/franiglesias/golden/internal/vfs"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type runnerStub struct {
+	code int
+	ran  bool
+}
+
+func (r *runnerStub) Run() int {
+	r.ran = true
+	return r.code
+}
+
+func TestGoldenMain(t *testing.T) {
+	g := NewUsingFs(vfs.NewMemFs())
+	runner := &runnerStub{code: 3}
+
+	code := g.Main(runner)
+
+	assert.True(t, runner.ran)
+	assert.Equal(t, 3, code)
+}
+
+func TestReportObsolete(t *testing.T) {
+	var fs *vfs.MemFs
+	var g *Golden
+
+	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
+		fs = vfs.NewMemFs()
+		g = NewUsingFs(fs)
+		_ = fs.WriteFile("testdata/TestOld.snap", []byte("old"))
+		g.usage.record(&helper.TSpy{T: t}, "testdata/TestNew.snap", g.global)
+	}
+
+	t.Run("should list obsolete snapshots", func(t *testing.T) {
+		setUp(t)
+		out := bytes.Buffer{}
+
+		g.reportObsolete(&out)
+
+		assert.Contains(t, out.String(), "golden: 1 obsolete snapshots found:\n  testdata/TestOld.snap\n")
+		exists, _ := fs.Exists("testdata/TestOld.snap")
+		assert.True(t, exists)
+	})
+
+	t.Run("should remove obsolete snapshots in prune mode", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_PRUNE", "1")
+		out := bytes.Buffer{}
+
+		g.reportObsolete(&out)
+
+		assert.Contains(t, out.String(), "golden: removed obsolete snapshot testdata/TestOld.snap")
+		exists, _ := fs.Exists("testdata/TestOld.snap")
+		assert.False(t, exists)
+	})
+
+	t.Run("should not remove obsolete snapshots in short mode", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_PRUNE", "1")
+		assert.NoError(t, flag.Set("test.short", "true"))
+		defer func() {
+			_ = flag.Set("test.short", "false")
+		}()
+		out := bytes.Buffer{}
+
+		g.reportObsolete(&out)
+
+		assert.Contains(t, out.String(), "golden: obsolete snapshots not removed: tests run with -test.short")
+		exists, _ := fs.Exists("testdata/TestOld.snap")
+		assert.True(t, exists)
+	})
+
+	t.Run("should not remove obsolete snapshots in folders outside the package", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_PRUNE", "1")
+		conf := g.global
+		conf.folder = "../shared"
+		_ = fs.WriteFile("../shared/TestNew/old_case.snap", []byte("old"))
+		g.usage.record(&helper.TSpy{T: t}, "../shared/TestNew.snap", conf)
+		out := bytes.Buffer{}
+
+		g.reportObsolete(&out)
+
+		assert.Contains(t, out.String(), "golden: obsolete snapshots not removed: snapshots folder ../shared is outside the package")
+		exists, _ := fs.Exists("../shared/TestNew/old_case.snap")
+		assert.True(t, exists)
+	})
+
+	t.Run("should report nothing without obsolete snapshots", func(t *testing.T) {
+		setUp(t)
+		_ = fs.Remove("testdata/TestOld.snap")
+		out := bytes.Buffer{}
+
+		g.reportObsolete(&out)
+
+		assert.Empty(t, out.String())
+	})
+}
+
+func TestOutsidePackage(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+
+	for folder, outside := range map[string]bool{
+		"testdata":                           false,
+		"":                                   false,
+		"./testdata/../other":                false,
+		"..":                                 true,
+		"../shared":                          true,
+		"testdata/../../shared":              true,
+		filepath.Join(wd, "testdata"):        false,
+		filepath.Join(filepath.Dir(wd), "x"): true,
+	} {
+		assert.Equal(t, outside, outsidePackage(folder), folder)
+	}
+}
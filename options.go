@@ -36,6 +36,55 @@ func verifyMode() Option {
 	}
 }
 
+/*
+Strict will execute this test in strict mode: if the snapshot doesn't exist,
+the test fails instead of creating it, and approval and update modes are not
+allowed. Use StrictInCI to activate it only in CI environments.
+*/
+func Strict() Option {
+	return func(c *Config) Option {
+		previous := c.strict
+		c.strict = true
+		return func(c *Config) Option {
+			c.strict = previous
+			return Strict()
+		}
+	}
+}
+
+/*
+StrictInCI will execute this test in strict mode when CI=true, as defined by
+most CI services. Use it with Defaults to protect all the tests in the
+pipeline, while keeping the usual behavior in local runs:
+
+	golden.Defaults(golden.StrictInCI())
+*/
+func StrictInCI() Option {
+	return func(c *Config) Option {
+		previous := c.strictCI
+		c.strictCI = true
+		return func(c *Config) Option {
+			c.strictCI = previous
+			return StrictInCI()
+		}
+	}
+}
+
+/*
+ReadOnly will execute this test without touching the filesystem. Any attempt to
+write the snapshot makes the test fail, showing what would have been written.
+*/
+func ReadOnly() Option {
+	return func(c *Config) Option {
+		previous := c.readOnly
+		c.readOnly = true
+		return func(c *Config) Option {
+			c.readOnly = previous
+			return ReadOnly()
+		}
+	}
+}
+
 func WithScrubbers(scrubbers ...Scrubber) Option {
 	return func(c *Config) Option {
 		c.scrubbers = scrubbers
@@ -73,6 +122,42 @@ func Extension(extension string) Option {
 	}
 }
 
+/*
+Layout configures how the snapshot path is built from the test name. The
+default is NestedLayout, that uses a folder for each parent test.
+
+	golden.Defaults(golden.Layout(golden.FlatLayout{}))
+*/
+func Layout(layout SnapshotLayout) Option {
+	return func(c *Config) Option {
+		previous := c.layout
+		c.layout = layout
+		return func(c *Config) Option {
+			return Layout(previous)
+		}
+	}
+}
+
+/*
+PathTemplate configures the snapshot path, relative to the snapshots folder,
+using placeholders. It takes precedence over Layout. Useful as default to
+standardize snapshot locations:
+
+	golden.Defaults(golden.PathTemplate("{package}/{test}/{subtest}{ext}"))
+
+Available placeholders: {package}, {file}, {test}, {subtest}, {index},
+{format} and {ext}. Custom names passed with Snapshot are not affected.
+*/
+func PathTemplate(template string) Option {
+	return func(c *Config) Option {
+		previous := c.template
+		c.template = template
+		return func(c *Config) Option {
+			return PathTemplate(previous)
+		}
+	}
+}
+
 func Reporter(reporter DiffReporter) Option {
 	return func(c *Config) Option {
 		previous := c.reporter
@@ -83,6 +168,22 @@ func Reporter(reporter DiffReporter) Option {
 	}
 }
 
+/*
+AddReporter adds a reporter to the configured one, instead of replacing it, so
+you can add reporters to the defaults:
+
+	golden.Defaults(golden.AddReporter(golden.NewDiffToolReporter(nil)))
+*/
+func AddReporter(reporter DiffReporter) Option {
+	return func(c *Config) Option {
+		previous := c.reporter
+		c.reporter = NewMultiReporter(previous, reporter)
+		return func(c *Config) Option {
+			return Reporter(previous)
+		}
+	}
+}
+
 /*
 Combine is a convenience function that wraps the values you pass to golden.Master() tests.
 
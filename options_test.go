@@ -20,6 +20,33 @@ func TestOptions(t *testing.T) {
 		assert.True(t, c.approve)
 	})
 
+	t.Run("should configure strict mode", func(t *testing.T) {
+		c := Config{strict: false}
+		option := Strict()
+		undo := option(&c)
+		assert.True(t, c.strict)
+		undo(&c)
+		assert.False(t, c.strict)
+	})
+
+	t.Run("should configure strict mode in CI", func(t *testing.T) {
+		c := Config{strictCI: false}
+		option := StrictInCI()
+		undo := option(&c)
+		assert.True(t, c.strictCI)
+		undo(&c)
+		assert.False(t, c.strictCI)
+	})
+
+	t.Run("should configure read-only mode", func(t *testing.T) {
+		c := Config{readOnly: false}
+		option := ReadOnly()
+		undo := option(&c)
+		assert.True(t, c.readOnly)
+		undo(&c)
+		assert.False(t, c.readOnly)
+	})
+
 	t.Run("should configure snapshot folder", func(t *testing.T) {
 		c := Config{folder: "testdata"}
 		option := Folder("a_folder")
@@ -40,4 +67,25 @@ func TestOptions(t *testing.T) {
 		option(&c)
 		assert.IsType(t, BetterDiffReporter{}, c.reporter)
 	})
+
+	t.Run("should add reporter", func(t *testing.T) {
+		c := Config{reporter: NewLineDiffReporter()}
+		option := AddReporter(NewCharDiffReporter())
+		option(&c)
+		assert.Equal(t, NewMultiReporter(NewLineDiffReporter(), NewCharDiffReporter()), c.reporter)
+	})
+
+	t.Run("should configure layout", func(t *testing.T) {
+		c := Config{layout: NestedLayout{}}
+		option := Layout(FlatLayout{})
+		option(&c)
+		assert.IsType(t, FlatLayout{}, c.layout)
+	})
+
+	t.Run("should configure path template", func(t *testing.T) {
+		c := Config{template: ""}
+		option := PathTemplate("{test}{ext}")
+		option(&c)
+		assert.Equal(t, "{test}{ext}", c.template)
+	})
 }
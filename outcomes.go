@@ -0,0 +1,103 @@
+package golden
+
+import (
+	"github.com/franiglesias/golden/internal/hunk"
+	"sync"
+)
+
+/*
+outcome describes the result of a verification, so it can be reported at the
+end of the run. The snapshot and the subject are kept only for failures.
+*/
+type outcome struct {
+	test    string
+	path    string
+	file    string
+	mode    string
+	passed  bool
+	size    int
+	added   int
+	removed int
+	want    string
+	got     string
+}
+
+/*
+Modes of the verifications in the reports
+*/
+const (
+	modeVerify   = "verify"
+	modeApproval = "approval"
+	modeCreated  = "created"
+	modeUpdate   = "update"
+	modeStrict   = "strict"
+)
+
+/*
+newOutcome computes the size of the snapshot after the verification and the
+number of lines added and removed in the subject
+*/
+func newOutcome(t Failable, path, file, mode string, passed bool, want, got string) outcome {
+	result := outcome{
+		test:   t.Name(),
+		path:   path,
+		file:   file,
+		mode:   mode,
+		passed: passed,
+		size:   len(got),
+	}
+	if mode == modeVerify || mode == modeStrict {
+		result.size = len(want)
+	}
+	if want != got {
+		for _, line := range hunk.Compute(want, got).Lines() {
+			switch line[0] {
+			case '+':
+				result.added++
+			case '-':
+				result.removed++
+			}
+		}
+	}
+	if !passed {
+		result.want = want
+		result.got = got
+	}
+	return result
+}
+
+/*
+outcomes collects the results of the verifications of a test run
+*/
+type outcomes struct {
+	sync.Mutex
+	list []outcome
+}
+
+func newOutcomes() *outcomes {
+	return &outcomes{}
+}
+
+func (o *outcomes) record(result outcome) {
+	o.Lock()
+	defer o.Unlock()
+	o.list = append(o.list, result)
+}
+
+func (o *outcomes) all() []outcome {
+	o.Lock()
+	defer o.Unlock()
+	return append([]outcome(nil), o.list...)
+}
+
+func (o *outcomes) failed() []outcome {
+	o.Lock()
+	defer o.Unlock()
+	var failed []outcome
+	for _, result := range o.list {
+		if !result.passed {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
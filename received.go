@@ -0,0 +1,56 @@
+package golden
+
+import "github.com/franiglesias/golden/internal/received"
+
+const receivedNote = "Received subject written to %s"
+
+/*
+receivedPath is the path of the file that keeps the subject of a failed
+verification, next to the snapshot:
+
+	testdata/TestSomething.snap -> testdata/TestSomething.received.snap
+*/
+func receivedPath(name string, ext string) string {
+	return received.Path(name, ext)
+}
+
+/*
+isReceived returns true if the file keeps the subject of a failed verification.
+Snapshots that only contain the mark in their names are not received files.
+*/
+func isReceived(name string) bool {
+	return received.Is(name)
+}
+
+/*
+keepReceived writes the subject next to the snapshot, so it can be inspected
+with other tools or approved later. Nothing is written in read-only mode.
+*/
+func (g *Golden) keepReceived(t Failable, name string, subject string, conf Config) error {
+	if conf.readOnlyMode() {
+		return nil
+	}
+	received := receivedPath(name, conf.ext)
+	err := g.writeSnapshot(received, subject)
+	if err != nil {
+		return err
+	}
+	logf(t, receivedNote, received)
+	return nil
+}
+
+/*
+discardReceived removes the received file of a previous failed verification,
+if any
+*/
+func (g *Golden) discardReceived(name string, conf Config) error {
+	if conf.readOnlyMode() {
+		return nil
+	}
+	received := receivedPath(name, conf.ext)
+	exists, err := g.snapshotExists(received)
+	if err != nil || !exists {
+		return err
+	}
+	return g.fs.Remove(received)
+}
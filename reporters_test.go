@@ -2,7 +2,10 @@ package golden_test
 
 import (
 	"github.com/franiglesias/golden"
+	"github.com/franiglesias/golden/internal/helper"
+	"github.com/franiglesias/golden/internal/vfs"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -57,3 +60,330 @@ func TestBetterDiffReporter(t *testing.T) {
 		assert.Contains(t, result, "+ Gotten that.")
 	})
 }
+
+func TestUnifiedDiffReporter(t *testing.T) {
+	helper.ClearModes(t)
+
+	reporter := golden.NewUnifiedDiffReporter()
+	ctx := golden.ReportContext{Path: "testdata/TestSomething.snap"}
+
+	t.Run("show no differences", func(t *testing.T) {
+		result := reporter.Differences("Same content", "Same content")
+		assert.Equal(t, "No differences found.", result)
+	})
+
+	t.Run("show differences with snapshot path", func(t *testing.T) {
+		result := reporter.ContextDifferences(ctx, "one\ntwo\nthree\n", "one\n2\nthree\n")
+		expected := "--- testdata/TestSomething.snap\n" +
+			"+++ testdata/TestSomething.snap\n" +
+			"@@ -1,3 +1,3 @@\n" +
+			" one\n" +
+			"-two\n" +
+			"+2\n" +
+			" three\n"
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("show differences without snapshot path", func(t *testing.T) {
+		result := reporter.Differences("Wanted this.\n", "Gotten that.\n")
+		assert.Equal(t, "--- snapshot\n+++ subject\n@@ -1 +1 @@\n-Wanted this.\n+Gotten that.\n", result)
+	})
+
+	t.Run("show separate hunks with context", func(t *testing.T) {
+		want := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+		got := "1\nTWO\n3\n4\n5\n6\n7\n8\nNINE\n10\n"
+		result := golden.NewUnifiedDiffReporterWithContext(1).ContextDifferences(ctx, want, got)
+		expected := "--- testdata/TestSomething.snap\n" +
+			"+++ testdata/TestSomething.snap\n" +
+			"@@ -1,3 +1,3 @@\n 1\n-2\n+TWO\n 3\n" +
+			"@@ -8,3 +8,3 @@\n 8\n-9\n+NINE\n 10\n"
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("join close hunks", func(t *testing.T) {
+		want := "1\n2\n3\n4\n5\n"
+		got := "ONE\n2\n3\n4\nFIVE\n"
+		result := reporter.ContextDifferences(ctx, want, got)
+		assert.Contains(t, result, "@@ -1,5 +1,5 @@\n-1\n+ONE\n 2\n 3\n 4\n-5\n+FIVE\n")
+		assert.Equal(t, 1, strings.Count(result, "@@ -"))
+	})
+
+	t.Run("show missing newline at end of file", func(t *testing.T) {
+		result := reporter.ContextDifferences(ctx, "one\ntwo", "one\ntwo\n")
+		assert.Contains(t, result, "@@ -1,2 +1,2 @@\n one\n-two\n\\ No newline at end of file\n+two\n")
+	})
+
+	t.Run("show new snapshot as file creation", func(t *testing.T) {
+		result := reporter.ContextDifferences(ctx, "", "one\ntwo\n")
+		assert.Equal(t, "--- /dev/null\n+++ testdata/TestSomething.snap\n@@ -0,0 +1,2 @@\n+one\n+two\n", result)
+	})
+
+	t.Run("show deleted lines", func(t *testing.T) {
+		result := reporter.ContextDifferences(ctx, "one\ntwo\n", "")
+		assert.Contains(t, result, "@@ -1,2 +0,0 @@\n-one\n-two\n")
+	})
+
+	t.Run("be used with snapshot path in Verify", func(t *testing.T) {
+		fs := vfs.NewMemFs()
+		gld := golden.NewUsingFs(fs)
+		tSpy := helper.TSpy{T: t}
+		err := fs.WriteFile("testdata/unified.snap", []byte("original\n"))
+		assert.NoError(t, err)
+
+		gld.Verify(&tSpy, "changed\n", golden.Snapshot("unified"), golden.Reporter(reporter))
+		helper.AssertReportContains(t, &tSpy, "--- testdata/unified.snap\n+++ testdata/unified.snap\n@@ -1 +1 @@\n-original\n+changed\n")
+	})
+}
+
+func TestSideBySideDiffReporter(t *testing.T) {
+
+	reporter := golden.NewSideBySideDiffReporterWithoutColor().WithWidth(43)
+
+	t.Run("show no differences", func(t *testing.T) {
+		result := reporter.Differences("Same content", "Same content")
+		assert.Equal(t, "No differences found.", result)
+	})
+
+	t.Run("show differences in two columns", func(t *testing.T) {
+		want := "id: 1\nname: Fran\ncity: Vigo\nzip: 36200\n"
+		got := "id: 1\nname: Frank\nzip: 36200\nphone: 555\n"
+		result := reporter.Differences(want, got)
+		expected := "Snapshot               Subject\n" +
+			"--------------------   --------------------\n" +
+			"id: 1                  id: 1\n" +
+			"name: Fran           | name: Frank\n" +
+			"city: Vigo           <\n" +
+			"zip: 36200             zip: 36200\n" +
+			"                     > phone: 555\n"
+		assert.Contains(t, result, "Differences found:")
+		assert.Contains(t, result, expected)
+	})
+
+	t.Run("truncate long lines", func(t *testing.T) {
+		result := reporter.Differences("short\n", "a very long line that doesn't fit in the column\n")
+		assert.Contains(t, result, "short                | a very long line th…\n")
+	})
+
+	t.Run("wrap long lines", func(t *testing.T) {
+		result := reporter.WithWrap().Differences("short\n", "a very long line that doesn't fit in the column\n")
+		assert.Contains(t, result, "short                | a very long line tha\n"+
+			"                     | t doesn't fit in the\n"+
+			"                     |  column\n")
+	})
+
+	t.Run("use width from environment", func(t *testing.T) {
+		t.Setenv("COLUMNS", "27")
+		result := golden.NewSideBySideDiffReporterWithoutColor().Differences("one\n", "two\n")
+		assert.Contains(t, result, "Snapshot       Subject\n------------   ------------\none          | two\n")
+	})
+
+	t.Run("highlight changed cells with color", func(t *testing.T) {
+		result := golden.NewSideBySideDiffReporter().WithWidth(43).Differences("same\none\n", "same\ntwo\n")
+		assert.Contains(t, result, "same                   same\n")
+		assert.Contains(t, result, "\x1b[31mone\x1b[0m                  | \x1b[32mtwo\x1b[0m\n")
+	})
+}
+
+func TestJSONDiffReporter(t *testing.T) {
+
+	reporter := golden.NewJSONDiffReporter()
+
+	t.Run("show no differences", func(t *testing.T) {
+		result := reporter.Differences(`{"a": 1}`, `{"a": 1}`)
+		assert.Equal(t, "No differences found.", result)
+	})
+
+	t.Run("show changes by JSON path", func(t *testing.T) {
+		want := `{
+  "customer": {"name": "Fran", "phone": "555"},
+  "items": [
+    {"name": "Book", "price": 10.5},
+    {"name": "Pen", "price": 1}
+  ],
+  "total": 11.5
+}`
+		got := `{
+  "customer": {"name": "Frank"},
+  "items": [
+    {"name": "Book", "price": 11.0},
+    {"name": "Pen", "price": 1},
+    {"name": "Notebook", "price": 2}
+  ],
+  "paid": true,
+  "total": 14.0
+}`
+		result := reporter.Differences(want, got)
+		expected := "~ $.customer.name: \"Fran\" -> \"Frank\"\n" +
+			"- $.customer.phone: \"555\"\n" +
+			"~ $.items[0].price: 10.5 -> 11.0\n" +
+			"+ $.items[2]: {\"name\":\"Notebook\",\"price\":2}\n" +
+			"+ $.paid: true\n" +
+			"~ $.total: 11.5 -> 14.0"
+		assert.Contains(t, result, "Differences found:")
+		assert.Contains(t, result, expected)
+	})
+
+	t.Run("show removed array elements and changed types", func(t *testing.T) {
+		result := reporter.Differences(`{"tags": ["a", "b"], "data": {"x": 1}}`, `{"tags": ["a"], "data": [1]}`)
+		assert.Contains(t, result, "~ $.data: {\"x\":1} -> [1]\n- $.tags[1]: \"b\"")
+	})
+
+	t.Run("quote keys that are not identifiers", func(t *testing.T) {
+		result := reporter.Differences(`{"first name": "Fran"}`, `{"first name": "Frank"}`)
+		assert.Contains(t, result, `~ $["first name"]: "Fran" -> "Frank"`)
+	})
+
+	t.Run("use line diff if not JSON", func(t *testing.T) {
+		result := reporter.Differences("Wanted this.", `{"a": 1}`)
+		assert.Contains(t, result, "-Wanted this.")
+		assert.Contains(t, result, `+{"a": 1}`)
+	})
+
+	t.Run("use line diff if only format changed", func(t *testing.T) {
+		result := reporter.Differences(`{"a": 1}`, "{\n  \"a\": 1\n}")
+		assert.Contains(t, result, `-{"a": 1}`)
+	})
+}
+
+func TestWordDiffReporter(t *testing.T) {
+
+	reporter := golden.NewWordDiffReporterWithoutColor()
+
+	t.Run("show no differences", func(t *testing.T) {
+		result := reporter.Differences("Same content", "Same content")
+		assert.Equal(t, "No differences found.", result)
+	})
+
+	t.Run("mark changed words", func(t *testing.T) {
+		want := "Dear Fran, your order 1234 is ready.\nThanks for buying with us.\n"
+		got := "Dear Frank, your order 1234 was shipped.\nThanks for buying with us.\n"
+		result := reporter.Differences(want, got)
+		assert.Contains(t, result, "Differences found:")
+		assert.Contains(t, result, "Dear [-Fran-]{+Frank+}, your order 1234 [-is-]{+was+} [-ready-]{+shipped+}.\nThanks for buying with us.\n")
+	})
+
+	t.Run("mark changed punctuation and spaces", func(t *testing.T) {
+		result := reporter.Differences("INFO  request done: 200", "INFO request done; 200")
+		assert.Contains(t, result, "INFO[-  -]{+ +}request done[-:-]{+;+} 200")
+	})
+
+	t.Run("mark changed lines", func(t *testing.T) {
+		result := reporter.Differences("one\ntwo\n", "one\n")
+		assert.Contains(t, result, "one\n[-two\n-]")
+	})
+
+	t.Run("show changes with color", func(t *testing.T) {
+		result := golden.NewWordDiffReporter().Differences("Hello Fran", "Hello Frank")
+		assert.Contains(t, result, "Hello \x1b[31mFran\x1b[0m\x1b[32mFrank\x1b[0m")
+	})
+}
+
+/*
+contextReporterSpy records the context it receives
+*/
+type contextReporterSpy struct {
+	name     string
+	contexts *[]golden.ReportContext
+}
+
+func (s contextReporterSpy) Differences(want, got string) string {
+	return s.ContextDifferences(golden.ReportContext{}, want, got)
+}
+
+func (s contextReporterSpy) ContextDifferences(ctx golden.ReportContext, want, got string) string {
+	*s.contexts = append(*s.contexts, ctx)
+	return s.name + ": " + want + " -> " + got
+}
+
+func TestMultiReporter(t *testing.T) {
+	helper.ClearModes(t)
+
+	t.Run("show no differences", func(t *testing.T) {
+		reporter := golden.NewMultiReporter(golden.NewLineDiffReporter(), golden.NewCharDiffReporter())
+		result := reporter.Differences("Same content", "Same content")
+		assert.Equal(t, "No differences found.", result)
+	})
+
+	t.Run("combine output of reporters", func(t *testing.T) {
+		reporter := golden.NewMultiReporter(golden.NewLineDiffReporter(), golden.NewCharDiffReporter())
+		result := reporter.Differences("Wanted this.", "Gotten that.")
+		assert.Contains(t, result, "-Wanted this.\n+Gotten that.")
+		assert.Contains(t, result, "(~~Wanted this~~)(++Gotten that++)")
+	})
+
+	t.Run("flatten multi reporters", func(t *testing.T) {
+		var contexts []golden.ReportContext
+		first := golden.NewMultiReporter(contextReporterSpy{name: "first", contexts: &contexts}, nil)
+		reporter := golden.NewMultiReporter(first, contextReporterSpy{name: "second", contexts: &contexts})
+		result := reporter.Differences("a", "b")
+		assert.Equal(t, "first: a -> b\nsecond: a -> b", result)
+	})
+
+	t.Run("pass snapshot path and test name to every reporter", func(t *testing.T) {
+		var contexts []golden.ReportContext
+		gld := golden.NewUsingFs(vfs.NewMemFs())
+		tSpy := helper.TSpy{T: t}
+
+		gld.Verify(&tSpy, "new", golden.Snapshot("multi"), golden.WaitApproval(), golden.Reporter(golden.NewMultiReporter(
+			contextReporterSpy{name: "first", contexts: &contexts},
+			contextReporterSpy{name: "second", contexts: &contexts},
+		)))
+
+		helper.AssertReportContains(t, &tSpy, "first:  -> new\nsecond:  -> new")
+		expected := golden.ReportContext{
+			Path:     "testdata/multi.snap",
+			TestName: "TestMultiReporter/pass_snapshot_path_and_test_name_to_every_reporter",
+			Mode:     "approval",
+			Format:   "json",
+		}
+		assert.Equal(t, []golden.ReportContext{expected, expected}, contexts)
+	})
+
+	t.Run("pass mode and scrubbers to reporters", func(t *testing.T) {
+		var contexts []golden.ReportContext
+		fs := vfs.NewMemFs()
+		gld := golden.NewUsingFs(fs)
+		tSpy := helper.TSpy{T: t}
+		_ = fs.WriteFile("testdata/context.snap", []byte("old"))
+		scrubber := golden.NewScrubber("[0-9]+", "<number>")
+
+		gld.Verify(&tSpy, "new 42", golden.Snapshot("context"), golden.WithScrubbers(scrubber), golden.Reporter(
+			contextReporterSpy{name: "spy", contexts: &contexts},
+		))
+
+		helper.AssertReportContains(t, &tSpy, "spy: old -> new <number>")
+		assert.Len(t, contexts, 1)
+		assert.Equal(t, "verify", contexts[0].Mode)
+		assert.Equal(t, "testdata/context.snap", contexts[0].Path)
+		assert.Equal(t, []golden.Scrubber{scrubber}, contexts[0].Scrubbers)
+	})
+
+	t.Run("add reporter to defaults", func(t *testing.T) {
+		var contexts []golden.ReportContext
+		fs := vfs.NewMemFs()
+		gld := golden.NewUsingFs(fs)
+		tSpy := helper.TSpy{T: t}
+		_ = fs.WriteFile("testdata/added.snap", []byte("old"))
+
+		gld.Defaults(golden.AddReporter(contextReporterSpy{name: "added", contexts: &contexts}))
+		gld.Verify(&tSpy, "new", golden.Snapshot("added"))
+
+		helper.AssertReportContains(t, &tSpy, "-old\n+new")
+		helper.AssertReportContains(t, &tSpy, "added: old -> new")
+	})
+}
+
+func TestAdaptReporter(t *testing.T) {
+	t.Run("adapt reporter without context", func(t *testing.T) {
+		reporter := golden.AdaptReporter(golden.NewLineDiffReporter())
+		result := reporter.ContextDifferences(golden.ReportContext{Path: "testdata/any.snap"}, "Wanted", "Gotten")
+		assert.Equal(t, golden.NewLineDiffReporter().Differences("Wanted", "Gotten"), result)
+	})
+
+	t.Run("keep reporter with context", func(t *testing.T) {
+		var contexts []golden.ReportContext
+		spy := contextReporterSpy{name: "spy", contexts: &contexts}
+		reporter := golden.AdaptReporter(spy)
+		assert.Equal(t, spy, reporter)
+	})
+}
@@ -0,0 +1,71 @@
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+/*
+writeReports writes the reports of the run that were requested
+*/
+func (g *Golden) writeReports(w io.Writer) {
+	reports := []struct {
+		name  string
+		path  string
+		write func(path string) error
+	}{
+		{name: "HTML", path: htmlReportPath(), write: g.writeHTMLReport},
+		{name: "JSON", path: jsonReportPath(), write: g.writeJSONReport},
+	}
+	for _, report := range reports {
+		if report.path == "" {
+			continue
+		}
+		err := report.write(report.path)
+		if err != nil {
+			_, _ = fmt.Fprintf(w, "golden: could not write %s report: %s\n", report.name, err)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "golden: %s report written to %s\n", report.name, report.path)
+	}
+}
+
+/*
+jsonOutcome is a line of the JSON report
+*/
+type jsonOutcome struct {
+	Test     string `json:"test"`
+	Snapshot string `json:"snapshot"`
+	Mode     string `json:"mode"`
+	Passed   bool   `json:"passed"`
+	Size     int    `json:"size"`
+	Added    int    `json:"added"`
+	Removed  int    `json:"removed"`
+}
+
+/*
+writeJSONReport writes a JSON object for each verification of the run, one per
+line, in the order they finished
+*/
+func (g *Golden) writeJSONReport(path string) error {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	for _, result := range g.outcomes.all() {
+		err := encoder.Encode(jsonOutcome{
+			Test:     result.test,
+			Snapshot: result.path,
+			Mode:     result.mode,
+			Passed:   result.passed,
+			Size:     result.size,
+			Added:    result.added,
+			Removed:  result.removed,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return g.fs.WriteFile(path, b.Bytes())
+}
@@ -0,0 +1,97 @@
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/franiglesias/golden/internal/helper"
+	"github.com/franiglesias/golden/internal/vfs"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestJSONReport(t *testing.T) {
+	var fs *vfs.MemFs
+	var g *Golden
+	var tSpy helper.TSpy
+
+	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
+		fs = vfs.NewMemFs()
+		g = NewUsingFs(fs)
+		tSpy = helper.TSpy{T: t}
+		t.Setenv("GOLDEN_JSON_REPORT", "reports/golden.jsonl")
+	}
+
+	report := func(t *testing.T) []map[string]any {
+		content, err := fs.ReadFile("reports/golden.jsonl")
+		assert.NoError(t, err)
+		var lines []map[string]any
+		for _, line := range strings.Split(strings.TrimSuffix(string(content), "\n"), "\n") {
+			var outcome map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(line), &outcome))
+			lines = append(lines, outcome)
+		}
+		return lines
+	}
+
+	t.Run("should report every verification", func(t *testing.T) {
+		setUp(t)
+		_ = fs.WriteFile("testdata/passing.snap", []byte("same"))
+		_ = fs.WriteFile("testdata/failing.snap", []byte("one\ntwo\nthree\n"))
+
+		g.Verify(&tSpy, "same", Snapshot("passing"))
+		g.Verify(&tSpy, "one\n2\nthree\nfour\n", Snapshot("failing"))
+		g.Verify(&tSpy, "new", Snapshot("created"))
+		g.Verify(&tSpy, "approved", Snapshot("approved"), WaitApproval())
+		out := bytes.Buffer{}
+		g.writeReports(&out)
+
+		assert.Equal(t, "golden: JSON report written to reports/golden.jsonl\n", out.String())
+		test := "TestJSONReport/should_report_every_verification"
+		assert.Equal(t, []map[string]any{
+			{"test": test, "snapshot": "testdata/passing.snap", "mode": "verify", "passed": true, "size": 4.0, "added": 0.0, "removed": 0.0},
+			{"test": test, "snapshot": "testdata/failing.snap", "mode": "verify", "passed": false, "size": 14.0, "added": 2.0, "removed": 1.0},
+			{"test": test, "snapshot": "testdata/created.snap", "mode": "created", "passed": true, "size": 3.0, "added": 0.0, "removed": 0.0},
+			{"test": test, "snapshot": "testdata/approved.snap", "mode": "approval", "passed": false, "size": 8.0, "added": 1.0, "removed": 0.0},
+		}, report(t))
+	})
+
+	t.Run("should report update mode", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_UPDATE", "1")
+		_ = fs.WriteFile("testdata/updated.snap", []byte("old"))
+
+		g.Verify(&tSpy, "new", Snapshot("updated"))
+		g.writeReports(&bytes.Buffer{})
+
+		outcome := report(t)[0]
+		assert.Equal(t, "update", outcome["mode"])
+		assert.Equal(t, true, outcome["passed"])
+		assert.Equal(t, 1.0, outcome["added"])
+		assert.Equal(t, 1.0, outcome["removed"])
+	})
+
+	t.Run("should write both reports", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_HTML_REPORT", "reports/golden.html")
+		out := bytes.Buffer{}
+
+		g.writeReports(&out)
+
+		assert.Contains(t, out.String(), "golden: HTML report written to reports/golden.html\n")
+		assert.Contains(t, out.String(), "golden: JSON report written to reports/golden.jsonl\n")
+	})
+
+	t.Run("should tell if report can't be written", func(t *testing.T) {
+		failing := vfs.NewFailingFs()
+		g = NewUsingFs(failing)
+		failing.WriteError = assert.AnError
+		t.Setenv("GOLDEN_JSON_REPORT", "reports/golden.jsonl")
+		out := bytes.Buffer{}
+
+		g.writeReports(&out)
+
+		assert.Contains(t, out.String(), "golden: could not write JSON report: "+assert.AnError.Error())
+	})
+}
@@ -0,0 +1,56 @@
+package golden
+
+import "sync"
+
+/*
+sequence counts the snapshots taken by each test in each path, so successive
+calls to Verify in the same test get their own snapshot instead of overwriting
+the first one. Calls that resolve to different paths, like Verify and Master,
+or another folder, don't need a number.
+*/
+type sequence struct {
+	sync.Mutex
+	counters map[string]map[string]int
+}
+
+func newSequence() *sequence {
+	return &sequence{
+		counters: make(map[string]map[string]int),
+	}
+}
+
+/*
+cleaner is implemented by *testing.T. It allows us to reset the counter of a
+test when it finishes, so running it again (-count=n) starts from the first
+snapshot
+*/
+type cleaner interface {
+	Cleanup(f func())
+}
+
+/*
+next returns the index for the next snapshot of the test in the path, starting
+with 1. The path is the one of the first snapshot.
+*/
+func (s *sequence) next(t Failable, path string) int {
+	s.Lock()
+	defer s.Unlock()
+
+	name := t.Name()
+	if s.counters[name] == nil {
+		s.counters[name] = make(map[string]int)
+		if c, ok := t.(cleaner); ok {
+			c.Cleanup(func() {
+				s.reset(name)
+			})
+		}
+	}
+	s.counters[name][path]++
+	return s.counters[name][path]
+}
+
+func (s *sequence) reset(name string) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.counters, name)
+}
@@ -0,0 +1,186 @@
+package golden
+
+import (
+	"fmt"
+	"github.com/franiglesias/golden/internal/hunk"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultColumns = 80
+	minColumnWidth = 10
+	colorRed       = "\x1b[31m"
+	colorGreen     = "\x1b[32m"
+	colorReset     = "\x1b[0m"
+)
+
+/*
+SideBySideDiffReporter shows the snapshot and the subject in two columns, like
+sdiff. Changed lines are marked with |, deleted lines with < and inserted lines
+with >. It is easier to read than a vertical diff for tabular snapshots, like
+the ones of Master.
+
+Lines longer than the column are truncated, unless wrapping is enabled. The
+width defaults to the COLUMNS environment variable, or 80.
+*/
+type SideBySideDiffReporter struct {
+	width int
+	wrap  bool
+	color bool
+}
+
+func NewSideBySideDiffReporter() SideBySideDiffReporter {
+	return SideBySideDiffReporter{
+		color: true,
+	}
+}
+
+func NewSideBySideDiffReporterWithoutColor() SideBySideDiffReporter {
+	return SideBySideDiffReporter{
+		color: false,
+	}
+}
+
+/*
+WithWidth returns a reporter that uses the given total width in characters
+*/
+func (s SideBySideDiffReporter) WithWidth(columns int) SideBySideDiffReporter {
+	s.width = columns
+	return s
+}
+
+/*
+WithWrap returns a reporter that wraps long lines instead of truncating them
+*/
+func (s SideBySideDiffReporter) WithWrap() SideBySideDiffReporter {
+	s.wrap = true
+	return s
+}
+
+func (s SideBySideDiffReporter) Differences(want, got string) string {
+	if want == got {
+		return noDifferences
+	}
+
+	column := s.columnWidth()
+	var b strings.Builder
+	s.writeRow(&b, column, ' ', "Snapshot", "Subject")
+	s.writeRow(&b, column, ' ', strings.Repeat("-", column), strings.Repeat("-", column))
+	for _, row := range sideBySideRows(hunk.Compute(want, got).Lines()) {
+		s.writeRow(&b, column, row.mark, row.left, row.right)
+	}
+	return fmt.Sprintf(diffHeaderFormat, b.String())
+}
+
+func (s SideBySideDiffReporter) columnWidth() int {
+	width := s.width
+	if width <= 0 {
+		width = terminalColumns()
+	}
+	column := (width - 3) / 2
+	if column < minColumnWidth {
+		column = minColumnWidth
+	}
+	return column
+}
+
+func terminalColumns() int {
+	columns, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || columns <= 0 {
+		return defaultColumns
+	}
+	return columns
+}
+
+/*
+writeRow writes a row, in several lines if wrapping is enabled
+*/
+func (s SideBySideDiffReporter) writeRow(b *strings.Builder, column int, mark rune, left, right string) {
+	lefts := s.fit(left, column)
+	rights := s.fit(right, column)
+	for len(lefts) < len(rights) {
+		lefts = append(lefts, "")
+	}
+	for len(rights) < len(lefts) {
+		rights = append(rights, "")
+	}
+	for i := range lefts {
+		l := lefts[i] + strings.Repeat(" ", column-len([]rune(lefts[i])))
+		line := s.paint(l, mark, colorRed) + " " + string(mark) + " " + s.paint(rights[i], mark, colorGreen)
+		b.WriteString(strings.TrimRight(line, " "))
+		b.WriteString("\n")
+	}
+}
+
+/*
+fit cuts the text in pieces no longer than the column, or truncates it
+*/
+func (s SideBySideDiffReporter) fit(text string, column int) []string {
+	runes := []rune(strings.ReplaceAll(text, "\t", "    "))
+	if len(runes) <= column {
+		return []string{string(runes)}
+	}
+	if !s.wrap {
+		return []string{string(runes[:column-1]) + "…"}
+	}
+	var pieces []string
+	for len(runes) > column {
+		pieces = append(pieces, string(runes[:column]))
+		runes = runes[column:]
+	}
+	return append(pieces, string(runes))
+}
+
+/*
+paint highlights the cells of changed rows
+*/
+func (s SideBySideDiffReporter) paint(cell string, mark rune, color string) string {
+	if !s.color || mark == ' ' || strings.TrimSpace(cell) == "" {
+		return cell
+	}
+	trimmed := strings.TrimRight(cell, " ")
+	return color + trimmed + colorReset + cell[len(trimmed):]
+}
+
+type sideBySideRow struct {
+	left  string
+	right string
+	mark  rune
+}
+
+/*
+sideBySideRows pairs deleted and inserted lines of a line diff as changed rows.
+The lines that can't be paired are shown alone.
+*/
+func sideBySideRows(lines []string) []sideBySideRow {
+	var rows []sideBySideRow
+	var deleted, inserted []string
+	flush := func() {
+		for i := 0; i < len(deleted) || i < len(inserted); i++ {
+			switch {
+			case i < len(deleted) && i < len(inserted):
+				rows = append(rows, sideBySideRow{left: deleted[i], right: inserted[i], mark: '|'})
+			case i < len(deleted):
+				rows = append(rows, sideBySideRow{left: deleted[i], mark: '<'})
+			default:
+				rows = append(rows, sideBySideRow{right: inserted[i], mark: '>'})
+			}
+		}
+		deleted, inserted = nil, nil
+	}
+	for _, line := range lines {
+		switch line[0] {
+		case '-':
+			deleted = append(deleted, line[1:])
+		case '+':
+			inserted = append(inserted, line[1:])
+		default:
+			flush()
+			rows = append(rows, sideBySideRow{left: line[1:], right: line[1:], mark: ' '})
+		}
+	}
+	flush()
+	return rows
+}
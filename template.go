@@ -0,0 +1,102 @@
+package golden
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*
+resolveTemplate builds the snapshot path from a PathTemplate, relative to the
+snapshots folder. Available placeholders:
+
+	{package}  name of the package of the test
+	{file}     name of the test file, without _test.go
+	{test}     name of the top level test
+	{subtest}  path of the subtest, empty for top level tests
+	{index}    number of the snapshot in the test: 1, 2, 3...
+	{format}   format of the normalizer, like json
+	{ext}      extension configured for the snapshot
+
+Empty placeholders don't leave empty folders behind, so "{test}/{subtest}{ext}"
+resolves to TestSomething.snap for top level tests. If the template has no
+{index}, successive snapshots of a test get the _2, _3... suffix.
+*/
+func (c Config) resolveTemplate(t Failable) string {
+	segments := sanitizeAll(strings.Split(t.Name(), "/"))
+
+	replacer := strings.NewReplacer(
+		"{package}", sanitize(c.source.pkg()),
+		"{file}", sanitize(strings.TrimSuffix(path.Base(c.source.file), "_test.go")),
+		"{test}", segments[0],
+		"{subtest}", strings.Join(segments[1:], "/"),
+		"{index}", strconv.Itoa(c.snapshotIndex()),
+		"{format}", c.format,
+		"{ext}", c.ext,
+	)
+	resolved := cleanTemplatePath(replacer.Replace(c.template))
+
+	if c.index > 1 && !strings.Contains(c.template, "{index}") {
+		suffix := "_" + strconv.Itoa(c.index)
+		if c.ext != "" && strings.HasSuffix(resolved, c.ext) {
+			return strings.TrimSuffix(resolved, c.ext) + suffix + c.ext
+		}
+		return resolved + suffix
+	}
+	return resolved
+}
+
+func (c Config) snapshotIndex() int {
+	if c.index < 1 {
+		return 1
+	}
+	return c.index
+}
+
+/*
+cleanTemplatePath removes empty folders and joins a trailing extension with
+the previous segment
+*/
+func cleanTemplatePath(p string) string {
+	var segments []string
+	for _, segment := range strings.Split(p, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	last := len(segments) - 1
+	if last > 0 && strings.HasPrefix(segments[last], ".") {
+		segments[last-1] += segments[last]
+		segments = segments[:last]
+	}
+	return strings.Join(segments, "/")
+}
+
+var testPlaceholders = regexp.MustCompile(`\{(test|subtest|index)\}`)
+
+/*
+templatePattern returns a regular expression matching the paths that the
+template can resolve to, including the snapshots folder. Placeholders that
+depend on the test match anything, and folders made empty by them may be
+missing.
+*/
+func (c Config) templatePattern() string {
+	fixed := strings.NewReplacer(
+		"{package}", sanitize(c.source.pkg()),
+		"{file}", sanitize(strings.TrimSuffix(path.Base(c.source.file), "_test.go")),
+		"{format}", c.format,
+		"{ext}", c.ext,
+	).Replace(c.template)
+
+	parts := testPlaceholders.Split(fixed, -1)
+	for i, part := range parts {
+		parts[i] = strings.ReplaceAll(regexp.QuoteMeta(part), "/", "/?")
+	}
+
+	prefix := ""
+	if c.folder != "" {
+		prefix = regexp.QuoteMeta(c.folder) + "/"
+	}
+	return "^" + prefix + strings.Join(parts, ".*") + "(_[0-9]+)?$"
+}
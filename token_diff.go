@@ -0,0 +1,62 @@
+package golden
+
+import (
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+type diffToken struct {
+	op   diffmatchpatch.Operation
+	text string
+}
+
+/*
+tokenDiff computes the differences between two sequences of tokens, like lines
+or words. Each distinct token is encoded as a rune, so the character diff works
+on whole tokens.
+*/
+func tokenDiff(want, got []string) []diffToken {
+	var tokens []string
+	index := make(map[string]rune)
+	encode := func(text []string) []rune {
+		var encoded []rune
+		for _, token := range text {
+			r, ok := index[token]
+			if !ok {
+				r = tokenRune(len(tokens))
+				index[token] = r
+				tokens = append(tokens, token)
+			}
+			encoded = append(encoded, r)
+		}
+		return encoded
+	}
+	a, b := encode(want), encode(got)
+
+	dmp := diffmatchpatch.New()
+	var result []diffToken
+	for _, d := range dmp.DiffMainRunes(a, b, false) {
+		for _, r := range d.Text {
+			result = append(result, diffToken{op: d.Type, text: tokens[runeToken(r)]})
+		}
+	}
+	return result
+}
+
+/*
+tokenRune and runeToken map the position of a token to a valid rune and back.
+Surrogates are skipped.
+*/
+func tokenRune(i int) rune {
+	r := rune(i + 1)
+	if r >= 0xD800 {
+		r += 0x800
+	}
+	return r
+}
+
+func runeToken(r rune) int {
+	if r >= 0xD800 {
+		r -= 0x800
+	}
+	return int(r) - 1
+}
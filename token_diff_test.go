@@ -0,0 +1,22 @@
+package golden
+
+import (
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTokenDiff(t *testing.T) {
+	t.Run("should keep tokens whole", func(t *testing.T) {
+		tokens := tokenDiff(splitWords("Dear Fran, hi"), splitWords("Dear Frank, hi"))
+		assert.Equal(t, []diffToken{
+			{op: diffmatchpatch.DiffEqual, text: "Dear"},
+			{op: diffmatchpatch.DiffEqual, text: " "},
+			{op: diffmatchpatch.DiffDelete, text: "Fran"},
+			{op: diffmatchpatch.DiffInsert, text: "Frank"},
+			{op: diffmatchpatch.DiffEqual, text: ","},
+			{op: diffmatchpatch.DiffEqual, text: " "},
+			{op: diffmatchpatch.DiffEqual, text: "hi"},
+		}, tokens)
+	})
+}
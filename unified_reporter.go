@@ -0,0 +1,191 @@
+package golden
+
+import (
+	"fmt"
+	"github.com/franiglesias/golden/internal/hunk"
+	"strings"
+)
+
+const noNewline = "\\ No newline at end of file\n"
+
+/*
+UnifiedDiffReporter shows differences in unified diff format, with the path of
+the snapshot in the headers, so the output of a failed test can be saved and
+applied to update the snapshot:
+
+	patch -p0 < failure.diff
+	git apply -p0 failure.diff
+*/
+type UnifiedDiffReporter struct {
+	context int
+}
+
+/*
+NewUnifiedDiffReporter shows three lines of context around changes, like diff -u
+*/
+func NewUnifiedDiffReporter() UnifiedDiffReporter {
+	return UnifiedDiffReporter{
+		context: 3,
+	}
+}
+
+func NewUnifiedDiffReporterWithContext(lines int) UnifiedDiffReporter {
+	if lines < 0 {
+		lines = 0
+	}
+	return UnifiedDiffReporter{
+		context: lines,
+	}
+}
+
+/*
+Differences uses generic names in the headers, because the path of the snapshot
+is unknown
+*/
+func (u UnifiedDiffReporter) Differences(want, got string) string {
+	return u.unified("snapshot", "subject", want, got)
+}
+
+func (u UnifiedDiffReporter) ContextDifferences(ctx ReportContext, want, got string) string {
+	return u.unified(ctx.Path, ctx.Path, want, got)
+}
+
+func (u UnifiedDiffReporter) unified(from, to string, want, got string) string {
+	if want == got {
+		return noDifferences
+	}
+	if want == "" {
+		from = "/dev/null"
+	}
+
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "--- %s\n+++ %s\n", from, to)
+	ends := newlineEnds{
+		want:        lineCount(want),
+		got:         lineCount(got),
+		wantMissing: want != "" && !strings.HasSuffix(want, "\n"),
+		gotMissing:  got != "" && !strings.HasSuffix(got, "\n"),
+	}
+	for _, h := range joinHunks(hunk.Compute(want, got).HunksWithContext(u.context)) {
+		writeHunk(&b, h, ends)
+	}
+	return b.String()
+}
+
+/*
+newlineEnds tells the number of lines of the snapshot and the subject, and if
+their last line lacks the line break, to mark it in the hunk that shows it
+*/
+type newlineEnds struct {
+	want        int
+	got         int
+	wantMissing bool
+	gotMissing  bool
+}
+
+/*
+joinHunks joins the hunks whose context touches, like diff -u does, because
+patch and git apply reject hunks that share lines. The hunks are the ones that
+golden approve -hunks numbers, so a joined hunk contains several of them.
+*/
+func joinHunks(hunks []hunk.Hunk) []hunk.Hunk {
+	var joined []hunk.Hunk
+	for _, h := range hunks {
+		last := len(joined) - 1
+		if last >= 0 {
+			prev := joined[last]
+			gap := h.Line - prev.Line - snapshotLines(prev.Changes)
+			if gap <= len(prev.After)+len(h.Before) {
+				var changes []string
+				changes = append(changes, prev.Changes...)
+				changes = append(changes, prev.After...)
+				changes = append(changes, h.Before[len(prev.After)+len(h.Before)-gap:]...)
+				changes = append(changes, h.Changes...)
+				prev.Changes = changes
+				prev.After = h.After
+				joined[last] = prev
+				continue
+			}
+		}
+		joined = append(joined, h)
+	}
+	return joined
+}
+
+/*
+snapshotLines counts the lines of the diff that belong to the snapshot
+*/
+func snapshotLines(lines []string) int {
+	count := 0
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "+") {
+			count++
+		}
+	}
+	return count
+}
+
+/*
+writeHunk writes the hunk in unified diff format
+*/
+func writeHunk(b *strings.Builder, h hunk.Hunk, ends newlineEnds) {
+	var lines []string
+	lines = append(lines, h.Before...)
+	lines = append(lines, h.Changes...)
+	lines = append(lines, h.After...)
+
+	wantStart, gotStart := h.Line-len(h.Before), h.SubjectLine-len(h.Before)
+	wantCount, gotCount := 0, 0
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "+") {
+			wantCount++
+		}
+		if !strings.HasPrefix(line, "-") {
+			gotCount++
+		}
+	}
+
+	_, _ = fmt.Fprintf(b, "@@ -%s +%s @@\n", hunkRange(wantStart, wantCount), hunkRange(gotStart, gotCount))
+	wantLine, gotLine := wantStart-1, gotStart-1
+	for _, line := range lines {
+		b.WriteString(line + "\n")
+		lastWant, lastGot := false, false
+		if !strings.HasPrefix(line, "+") {
+			wantLine++
+			lastWant = wantLine == ends.want && ends.wantMissing
+		}
+		if !strings.HasPrefix(line, "-") {
+			gotLine++
+			lastGot = gotLine == ends.got && ends.gotMissing
+		}
+		if lastWant || lastGot {
+			b.WriteString(noNewline)
+		}
+	}
+}
+
+/*
+lineCount returns the number of lines of text, counting the last one even if it
+doesn't end with a line break
+*/
+func lineCount(text string) int {
+	count := strings.Count(text, "\n")
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		count++
+	}
+	return count
+}
+
+/*
+hunkRange formats the range of lines of a hunk. Empty ranges point to the line
+before them.
+*/
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
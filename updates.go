@@ -0,0 +1,39 @@
+package golden
+
+import (
+	"fmt"
+	"io"
+)
+
+/*
+reportUpdates prints the snapshots touched in update mode, because go test
+only shows the logs of the tests with -v
+*/
+func (g *Golden) reportUpdates(w io.Writer) {
+	if !updateRequested() {
+		return
+	}
+
+	var created, updated []string
+	unchanged := 0
+	for _, result := range g.outcomes.all() {
+		switch {
+		case result.mode == modeCreated:
+			created = append(created, result.path)
+		case result.mode != modeUpdate:
+			continue
+		case result.added+result.removed > 0:
+			updated = append(updated, result.path)
+		default:
+			unchanged++
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "golden: %d snapshots created, %d updated, %d unchanged\n", len(created), len(updated), unchanged)
+	for _, path := range created {
+		_, _ = fmt.Fprintf(w, "  created %s\n", path)
+	}
+	for _, path := range updated {
+		_, _ = fmt.Fprintf(w, "  updated %s\n", path)
+	}
+}
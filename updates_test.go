@@ -0,0 +1,49 @@
+package golden
+
+import (
+	"bytes"
+	"github.com/franiglesias/golden/internal/helper"
+	"github.com/franiglesias/golden/internal/vfs"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReportUpdates(t *testing.T) {
+	var fs *vfs.MemFs
+	var g *Golden
+	var tSpy helper.TSpy
+
+	setUp := func(t *testing.T) {
+		helper.ClearModes(t)
+		fs = vfs.NewMemFs()
+		g = NewUsingFs(fs)
+		tSpy = helper.TSpy{T: t}
+	}
+
+	t.Run("should list created and updated snapshots", func(t *testing.T) {
+		setUp(t)
+		t.Setenv("GOLDEN_UPDATE", "1")
+		_ = fs.WriteFile("testdata/changed.snap", []byte("old"))
+		_ = fs.WriteFile("testdata/same.snap", []byte("same"))
+
+		g.Verify(&tSpy, "new", Snapshot("created"))
+		g.Verify(&tSpy, "new", Snapshot("changed"))
+		g.Verify(&tSpy, "same", Snapshot("same"))
+		out := bytes.Buffer{}
+		g.reportUpdates(&out)
+
+		assert.Equal(t, "golden: 1 snapshots created, 1 updated, 1 unchanged\n"+
+			"  created testdata/created.snap\n"+
+			"  updated testdata/changed.snap\n", out.String())
+	})
+
+	t.Run("should report nothing out of update mode", func(t *testing.T) {
+		setUp(t)
+
+		g.Verify(&tSpy, "new", Snapshot("created"))
+		out := bytes.Buffer{}
+		g.reportUpdates(&out)
+
+		assert.Empty(t, out.String())
+	})
+}
@@ -0,0 +1,99 @@
+package golden
+
+import (
+	"fmt"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"strings"
+	"unicode"
+)
+
+/*
+WordDiffReporter marks the words that were deleted or inserted inline, so
+small changes in long lines of prose or logs are easy to spot. Text is split in
+words, whitespace and punctuation. Without color, changes are marked like in
+git diff --word-diff:
+
+	Dear [-Fran,-]{+Frank,+} your order is ready.
+*/
+type WordDiffReporter struct {
+	color bool
+}
+
+func NewWordDiffReporter() WordDiffReporter {
+	return WordDiffReporter{
+		color: true,
+	}
+}
+
+func NewWordDiffReporterWithoutColor() WordDiffReporter {
+	return WordDiffReporter{
+		color: false,
+	}
+}
+
+func (w WordDiffReporter) Differences(want, got string) string {
+	if want == got {
+		return noDifferences
+	}
+
+	var b, deleted, inserted strings.Builder
+	flush := func() {
+		if deleted.Len() > 0 {
+			b.WriteString(w.mark(deleted.String(), "[-", "-]", colorRed))
+		}
+		if inserted.Len() > 0 {
+			b.WriteString(w.mark(inserted.String(), "{+", "+}", colorGreen))
+		}
+		deleted.Reset()
+		inserted.Reset()
+	}
+	for _, token := range tokenDiff(splitWords(want), splitWords(got)) {
+		switch token.op {
+		case diffmatchpatch.DiffDelete:
+			deleted.WriteString(token.text)
+		case diffmatchpatch.DiffInsert:
+			inserted.WriteString(token.text)
+		default:
+			flush()
+			b.WriteString(token.text)
+		}
+	}
+	flush()
+	return fmt.Sprintf(diffHeaderFormat, b.String())
+}
+
+func (w WordDiffReporter) mark(text string, open, close string, color string) string {
+	if w.color {
+		return color + text + colorReset
+	}
+	return open + text + close
+}
+
+/*
+splitWords splits text in words, runs of spaces, line breaks and punctuation
+signs. Joining the result gives back the text.
+*/
+func splitWords(text string) []string {
+	var words []string
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		j := i + 1
+		switch {
+		case isWordRune(runes[i]):
+			for j < len(runes) && isWordRune(runes[j]) {
+				j++
+			}
+		case runes[i] != '\n' && unicode.IsSpace(runes[i]):
+			for j < len(runes) && runes[j] != '\n' && unicode.IsSpace(runes[j]) {
+				j++
+			}
+		}
+		words = append(words, string(runes[i:j]))
+		i = j
+	}
+	return words
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}